@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/config"
+	"gowebsite/internal/models"
+	"gowebsite/internal/repository"
+	"gowebsite/internal/service"
+	"gowebsite/pkg/db/postgres"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runExport runs the export subcommand and returns the exit code.
+func runExport(ctx context.Context, cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: main export [-format json|yaml] [-o FILE]")
+		flags.PrintDefaults()
+	}
+	format := flags.String("format", "", "bundle format, by default taken from the -o extension, else json")
+	output := flags.String("o", "-", "file to write the bundle to, - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+	if err := exportCommand(ctx, cfg, bundleFormat(*format, *output), *output); err != nil {
+		fmt.Fprintln(os.Stderr, "export:", err)
+		return 1
+	}
+	return 0
+}
+
+// runImport runs the import subcommand and returns the exit code.
+func runImport(ctx context.Context, cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: main import [-mode merge|replace] [-dry-run] [-format json|yaml] FILE|-")
+		flags.PrintDefaults()
+	}
+	mode := flags.String("mode", string(models.ImportMerge), "merge keeps records missing from the bundle, replace moves them to the trash")
+	dryRun := flags.Bool("dry-run", false, "report the changes without applying them")
+	format := flags.String("format", "", "bundle format, by default taken from the file extension, else json")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	opts := models.ImportOptions{Mode: models.ImportMode(*mode), DryRun: *dryRun}
+	if err := importCommand(ctx, cfg, bundleFormat(*format, flags.Arg(0)), flags.Arg(0), opts); err != nil {
+		fmt.Fprintln(os.Stderr, "import:", err)
+		return 1
+	}
+	return 0
+}
+
+// runApply runs the apply subcommand and returns the exit code.
+func runApply(ctx context.Context, cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("apply", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: main apply [-prune] [-dry-run] [-format json|yaml] FILE|-")
+		flags.PrintDefaults()
+	}
+	prune := flags.Bool("prune", false, "move technologies and projects missing from the file to the trash")
+	dryRun := flags.Bool("dry-run", false, "print the plan without applying it")
+	format := flags.String("format", "", "file format, by default taken from the file extension, else json")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	opts := models.ImportOptions{Mode: models.ImportMerge, DryRun: *dryRun}
+	if *prune {
+		opts.Mode = models.ImportReplace
+	}
+	if err := applyCommand(ctx, cfg, bundleFormat(*format, flags.Arg(0)), flags.Arg(0), opts, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "apply:", err)
+		return 1
+	}
+	return 0
+}
+
+// bundleFormat returns format, or the format the extension of path implies.
+func bundleFormat(format, path string) models.BundleFormat {
+	if format != "" {
+		return models.BundleFormat(format)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return models.BundleYAML
+	}
+	return models.BundleJSON
+}
+
+func exportCommand(ctx context.Context, cfg *config.Config, format models.BundleFormat, path string) error {
+	if !format.IsValid() {
+		return fmt.Errorf("format must be json or yaml, got %q", format)
+	}
+	portfolio, closeDB, err := bundleService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	bundle, err := portfolio.ExportBundle(ctx)
+	if err != nil {
+		return err
+	}
+	if path == "-" {
+		return models.EncodeBundle(os.Stdout, bundle, format)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := models.EncodeBundle(file, bundle, format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func importCommand(ctx context.Context, cfg *config.Config, format models.BundleFormat, path string, opts models.ImportOptions) error {
+	bundle, err := readBundle(format, path)
+	if err != nil {
+		return err
+	}
+
+	portfolio, closeDB, err := bundleService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	report, err := portfolio.ImportBundle(ctx, bundle, opts)
+	if err != nil {
+		return describeError(err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func applyCommand(ctx context.Context, cfg *config.Config, format models.BundleFormat, path string, opts models.ImportOptions, out io.Writer) error {
+	bundle, err := readBundle(format, path)
+	if err != nil {
+		return err
+	}
+
+	portfolio, closeDB, err := bundleService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	plan, err := portfolio.ApplyBundle(ctx, bundle, opts)
+	if err != nil {
+		return describeError(err)
+	}
+	printPlan(out, plan)
+	switch {
+	case plan.Empty():
+	case opts.DryRun:
+		fmt.Fprintln(out, "Dry run, nothing was changed.")
+	default:
+		fmt.Fprintln(out, "Applied.")
+	}
+	return nil
+}
+
+// readBundle decodes the bundle at path, - for stdin.
+func readBundle(format models.BundleFormat, path string) (*models.Bundle, error) {
+	if !format.IsValid() {
+		return nil, fmt.Errorf("format must be json or yaml, got %q", format)
+	}
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		in = file
+	}
+	return models.DecodeBundle(in, format)
+}
+
+// printPlan writes the steps of plan that change something, marked + for
+// creates, ~ for updates with their changed fields and - for deletes,
+// followed by their count.
+func printPlan(out io.Writer, plan *models.Plan) {
+	if plan.Empty() {
+		fmt.Fprintln(out, "No changes, the database matches the file.")
+		return
+	}
+	signs := map[models.PlanAction]string{models.PlanCreate: "+", models.PlanUpdate: "~", models.PlanDelete: "-"}
+	for _, step := range plan.Steps {
+		if step.Action == models.PlanKeep {
+			continue
+		}
+		fmt.Fprintf(out, "%s %s %q\n", signs[step.Action], step.Entity, step.Name)
+		for _, change := range step.Changes {
+			fmt.Fprintf(out, "    %s: %s -> %s\n", change.Field, change.Before, change.After)
+		}
+	}
+	fmt.Fprintf(out, "\nPlan: %d to create, %d to update, %d to delete.\n",
+		plan.Count(models.PlanCreate), plan.Count(models.PlanUpdate), plan.Count(models.PlanDelete))
+}
+
+// describeError lists the field errors of a validation error, one per
+// line, after its message.
+func describeError(err error) error {
+	appErr, ok := apperrors.As(err)
+	if !ok || len(appErr.Fields) == 0 {
+		return err
+	}
+	var b strings.Builder
+	b.WriteString(appErr.Message)
+	for _, field := range appErr.Fields {
+		fmt.Fprintf(&b, "\n  %s: %s", field.Field, field.Message)
+	}
+	return errors.New(b.String())
+}
+
+// bundleService connects to the database for export and import. Neither
+// touches asset files, so the service goes without storage.
+func bundleService(ctx context.Context, cfg *config.Config) (*service.PortfolioService, func(), error) {
+	db, err := postgres.New(ctx, cfg.PostgresConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	portfolio := service.NewPortfolioService(repository.NewPortfolioRepository(db), nil, cfg.AssetConfig)
+	return portfolio, func() { db.Close() }, nil
+}
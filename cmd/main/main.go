@@ -3,9 +3,13 @@ package main
 import (
 	"context"
 	"gowebsite/internal/config"
+	"gowebsite/internal/repository"
+	"gowebsite/internal/service"
+	"gowebsite/internal/site"
 	"gowebsite/internal/transport/rest"
 	"gowebsite/pkg/db/postgres"
 	"gowebsite/pkg/logger"
+	"gowebsite/pkg/storage"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,6 +17,15 @@ import (
 	"go.uber.org/zap"
 )
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from /auth/login as "Bearer <token>"
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description Scoped API key as "ApiKey <key>"
 func main() {
 	ctx := context.Background()
 	mainLogger := logger.New()
@@ -24,13 +37,53 @@ func main() {
 		mainLogger.Fatal(ctx, "failed to load config")
 	}
 	mainLogger.Debug(ctx, "Config loaded", zap.Any("config", cfg))
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(runMigrate(ctx, cfg, os.Args[2:]))
+		case "export":
+			os.Exit(runExport(ctx, cfg, os.Args[2:]))
+		case "import":
+			os.Exit(runImport(ctx, cfg, os.Args[2:]))
+		case "apply":
+			os.Exit(runApply(ctx, cfg, os.Args[2:]))
+		case "build-static":
+			os.Exit(runBuildStatic(ctx, cfg, os.Args[2:]))
+		}
+	}
+	if cfg.SiteConfig.URL == site.LocalURL {
+		mainLogger.Warn(ctx, "SITE_URL is not set, the feeds link to and identify their entries by "+site.LocalURL)
+	}
+	if len(cfg.JWTSecret) < 32 {
+		mainLogger.Fatal(ctx, "AUTH_JWT_SECRET must be at least 32 characters long")
+	}
 	db, err := postgres.New(ctx, cfg.PostgresConfig)
 	if err != nil {
 		mainLogger.Fatal(ctx, "failed to connect to database", zap.Error(err))
 	}
 	mainLogger.Debug(ctx, "Database connected")
 
-	RESTServer := rest.NewRESTServer(ctx, db, cfg.RESTServerPort, cfg.RESTServerHost)
+	if cfg.AutoMigrate {
+		if err := migrateOnStart(ctx, db); err != nil {
+			mainLogger.Fatal(ctx, "failed to migrate database", zap.Error(err))
+		}
+		mainLogger.Info(ctx, "Database migrated")
+	}
+
+	authService := service.NewAuthService(repository.NewAuthRepository(db), cfg.AuthConfig)
+	if err := authService.EnsureAdmin(ctx); err != nil {
+		mainLogger.Fatal(ctx, "failed to create admin user", zap.Error(err))
+	}
+
+	store, err := storage.New(ctx, cfg.StorageConfig)
+	if err != nil {
+		mainLogger.Fatal(ctx, "failed to set up storage", zap.Error(err))
+	}
+
+	RESTServer, err := rest.NewRESTServer(ctx, db, store, cfg)
+	if err != nil {
+		mainLogger.Fatal(ctx, "failed to set up server", zap.Error(err))
+	}
 
 	go func() {
 		if err := RESTServer.Run(ctx); err != nil {
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gowebsite/internal/config"
+	"gowebsite/migrations"
+	"gowebsite/pkg/db/postgres"
+	"io"
+	"os"
+	"strconv"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  up [N]         apply all pending migrations, or the next N
+  down [N]       roll back the last N migrations, 1 by default
+  status         show the applied version and the pending migrations
+  create NAME    add empty up and down files to MIGRATIONS_DIR
+`
+
+// runMigrate runs the migrate subcommand and returns the exit code.
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	if err := migrateCommand(ctx, cfg, args[0], args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+	return 0
+}
+
+func migrateCommand(ctx context.Context, cfg *config.Config, command string, args []string, out io.Writer) error {
+	if command == "create" {
+		if len(args) != 1 {
+			return fmt.Errorf("create takes the name of the migration")
+		}
+		paths, err := postgres.CreateMigration(cfg.MigrationsDir, args[0])
+		for _, path := range paths {
+			fmt.Fprintln(out, "created", path)
+		}
+		return err
+	}
+
+	steps := 0
+	switch command {
+	case "up", "down":
+		if len(args) > 1 {
+			return fmt.Errorf("%s takes at most one argument", command)
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("N must be a positive number, got %q", args[0])
+			}
+			steps = n
+		} else if command == "down" {
+			steps = 1
+		}
+	case "status":
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, migrateUsage)
+	}
+
+	db, err := postgres.New(ctx, cfg.PostgresConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	migrator, err := postgres.NewMigrator(ctx, db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch {
+	case command == "down":
+		err = migrator.Steps(-steps)
+	case command == "up" && steps > 0:
+		err = migrator.Steps(steps)
+	case command == "up":
+		err = migrator.Up()
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "version: %d of %d\n", status.Version, status.Latest)
+	if status.Dirty {
+		fmt.Fprintln(out, "dirty: the last migration failed, fix the database and force its version")
+	}
+	for _, name := range status.Pending {
+		fmt.Fprintln(out, "pending:", name)
+	}
+	return nil
+}
+
+// migrateOnStart applies the pending migrations before the server starts.
+func migrateOnStart(ctx context.Context, db *postgres.DB) error {
+	migrator, err := postgres.NewMigrator(ctx, db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+	return migrator.Up()
+}
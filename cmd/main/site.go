@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"gowebsite/internal/config"
+	"gowebsite/internal/repository"
+	"gowebsite/internal/service"
+	"gowebsite/internal/site"
+	"gowebsite/pkg/db/postgres"
+	"gowebsite/pkg/storage"
+	"os"
+)
+
+// runBuildStatic runs the build-static subcommand and returns the exit
+// code. The flags override the SITE_* settings.
+func runBuildStatic(ctx context.Context, cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("build-static", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: main build-static [-o DIR] [-theme DIR] [-base PATH]")
+		flags.PrintDefaults()
+	}
+	siteCfg := cfg.SiteConfig
+	flags.StringVar(&siteCfg.OutputDir, "o", siteCfg.OutputDir, "directory to write the site to, replacing a previous build")
+	flags.StringVar(&siteCfg.ThemeDir, "theme", siteCfg.ThemeDir, "directory with templates and static files overriding the default theme")
+	flags.StringVar(&siteCfg.BasePath, "base", siteCfg.BasePath, "path the site is served under")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+	if siteCfg.URL == site.LocalURL {
+		fmt.Fprintln(os.Stderr, "build-static: warning: SITE_URL is not set, the feeds link to and identify their entries by "+site.LocalURL)
+	}
+	if err := buildStaticCommand(ctx, cfg, siteCfg); err != nil {
+		fmt.Fprintln(os.Stderr, "build-static:", err)
+		return 1
+	}
+	return 0
+}
+
+func buildStaticCommand(ctx context.Context, cfg *config.Config, siteCfg site.SiteConfig) error {
+	db, err := postgres.New(ctx, cfg.PostgresConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	store, err := storage.New(ctx, cfg.StorageConfig)
+	if err != nil {
+		return err
+	}
+	portfolio := service.NewPortfolioService(repository.NewPortfolioRepository(db), store, cfg.AssetConfig)
+
+	manifest, err := site.Build(ctx, portfolio, siteCfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Built %d files into %s\n", len(manifest.Files), siteCfg.OutputDir)
+	return nil
+}
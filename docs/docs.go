@@ -15,95 +15,58 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/portfolio/projects": {
+        "/auth/api-keys": {
             "get": {
-                "description": "Get project list",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Get API keys without their secrets",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Portfolio"
-                ],
-                "summary": "Project list",
-                "parameters": [
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Language ID",
-                        "name": "tech_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Is active",
-                        "name": "is_active",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Is archived",
-                        "name": "is_archived",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Is developing",
-                        "name": "is_developing",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort field",
-                        "name": "sort_field",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort order",
-                        "name": "sort_order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit of projects",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset of projects",
-                        "name": "Offset",
-                        "in": "query"
-                    }
+                    "Auth"
                 ],
+                "summary": "API key list",
                 "responses": {
                     "200": {
-                        "description": "Project",
+                        "description": "API keys",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Project"
+                                "$ref": "#/definitions/models.APIKey"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {}
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Create project and write to database",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a scoped API key for automation clients. The key is only returned once",
                 "consumes": [
                     "application/json"
                 ],
@@ -111,106 +74,128 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Portfolio"
+                    "Auth"
                 ],
-                "summary": "Create Project",
+                "summary": "Create API key",
                 "parameters": [
                     {
-                        "description": "Project title",
-                        "name": "title",
+                        "description": "API key",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.APIKeyRequest"
                         }
-                    },
-                    {
-                        "description": "Project version",
-                        "name": "version",
-                        "in": "body",
-                        "required": true,
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "API key",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.CreatedAPIKey"
                         }
                     },
-                    {
-                        "description": "Project description",
-                        "name": "description",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
-                    {
-                        "description": "Language ID",
-                        "name": "language_id",
-                        "in": "body",
-                        "required": true,
+                    "401": {
+                        "description": "Authentication required",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
-                    {
-                        "description": "Is active",
-                        "name": "isActive",
-                        "in": "body",
-                        "required": true,
+                    "403": {
+                        "description": "Admin role required",
                         "schema": {
-                            "type": "boolean"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
-                    {
-                        "description": "Is archived",
-                        "name": "isArchived",
-                        "in": "body",
-                        "required": true,
+                    "422": {
+                        "description": "Invalid scope, expiry or quota",
                         "schema": {
-                            "type": "boolean"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
-                    {
-                        "description": "Is developing",
-                        "name": "isDeveloping",
-                        "in": "body",
-                        "required": true,
+                    "500": {
+                        "description": "Internal error",
                         "schema": {
-                            "type": "boolean"
+                            "$ref": "#/definitions/problem.Problem"
                         }
-                    },
+                    }
+                }
+            }
+        },
+        "/auth/api-keys/{id}": {
+            "delete": {
+                "security": [
                     {
-                        "description": "Links",
-                        "name": "links",
-                        "in": "body",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke API key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Project ID",
+                        "description": "Message",
                         "schema": {
-                            "type": "integer"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
                         "description": "Bad request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
             }
         },
-        "/portfolio/projects/{id}": {
-            "get": {
-                "description": "Get project",
+        "/auth/login": {
+            "post": {
+                "description": "Exchange email and password for an access and a refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -218,41 +203,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Portfolio"
+                    "Auth"
                 ],
-                "summary": "Project",
+                "summary": "Login",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Credentials"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Project",
+                        "description": "Tokens",
                         "schema": {
-                            "$ref": "#/definitions/models.Project"
+                            "$ref": "#/definitions/models.TokenPair"
                         }
                     },
                     "400": {
                         "description": "Bad request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
-                    "404": {
-                        "description": "Project nor found",
-                        "schema": {}
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete project",
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Revoke a refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -260,16 +255,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Portfolio"
+                    "Auth"
                 ],
-                "summary": "Delete Project",
+                "summary": "Logout",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh token",
+                        "name": "refresh",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -282,16 +279,22 @@ const docTemplate = `{
                     },
                     "400": {
                         "description": "Bad request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
-            },
-            "patch": {
-                "description": "Update project",
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new token pair. Refresh tokens are single use",
                 "consumes": [
                     "application/json"
                 ],
@@ -299,108 +302,103 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Portfolio"
+                    "Auth"
                 ],
-                "summary": "Update Project",
+                "summary": "Refresh tokens",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Project title",
-                        "name": "title",
+                        "description": "Refresh token",
+                        "name": "refresh",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.RefreshRequest"
                         }
-                    },
-                    {
-                        "description": "Project version",
-                        "name": "version",
-                        "in": "body",
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.TokenPair"
                         }
                     },
-                    {
-                        "description": "Project description",
-                        "name": "description",
-                        "in": "body",
+                    "400": {
+                        "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
-                    {
-                        "description": "Language ID",
-                        "name": "language_id",
-                        "in": "body",
+                    "401": {
+                        "description": "Invalid refresh token",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
-                    {
-                        "description": "Is active",
-                        "name": "isActive",
-                        "in": "body",
+                    "500": {
+                        "description": "Internal error",
                         "schema": {
-                            "type": "boolean"
+                            "$ref": "#/definitions/problem.Problem"
                         }
-                    },
-                    {
-                        "description": "Is archived",
-                        "name": "isArchived",
-                        "in": "body",
-                        "schema": {
-                            "type": "boolean"
-                        }
-                    },
-                    {
-                        "description": "Is developing",
-                        "name": "isDeveloping",
-                        "in": "body",
-                        "schema": {
-                            "type": "boolean"
-                        }
-                    },
+                    }
+                }
+            }
+        },
+        "/portfolio/export": {
+            "get": {
+                "description": "Export every technology and project as a versioned bundle, projects referring to technologies by name. The bundle is imported with the import command.",
+                "produces": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Export portfolio",
+                "parameters": [
                     {
-                        "description": "Links",
-                        "name": "links",
-                        "in": "body",
-                        "schema": {
-                            "type": "string"
-                        }
+                        "enum": [
+                            "json",
+                            "yaml"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Bundle format",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Message",
+                        "description": "Bundle",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Bundle"
+                        },
+                        "headers": {
+                            "Content-Disposition": {
+                                "type": "string",
+                                "description": "Suggested file name"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad request",
-                        "schema": {}
-                    },
-                    "404": {
-                        "description": "Technology not found",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
             }
         },
-        "/portfolio/techs": {
+        "/portfolio/projects": {
             "get": {
-                "description": "Get technology list",
+                "description": "Get project list. With q, only projects matching the full-text search are listed, by relevance unless sorted otherwise, with highlights",
                 "consumes": [
                     "application/json"
                 ],
@@ -410,27 +408,76 @@ const docTemplate = `{
                 "tags": [
                     "Portfolio"
                 ],
-                "summary": "Technology list",
+                "summary": "Project list",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Full-text search over title, description and technology names",
+                        "name": "q",
+                        "in": "query"
+                    },
                     {
                         "type": "array",
                         "items": {
                             "type": "integer"
                         },
-                        "collectionFormat": "csv",
                         "description": "Technology ID",
                         "name": "tech_id",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "any",
+                            "all",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "How tech_id is matched: any (default), all or none",
+                        "name": "tech_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "description": "Exclude projects using any of these technologies",
+                        "name": "exclude_tech_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Is active",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Is archived",
+                        "name": "is_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Is developing",
+                        "name": "is_developing",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields, prefix with - for descending. relevance requires q",
+                        "name": "sort",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Sort field",
+                        "description": "Sort field (deprecated, use sort)",
                         "name": "sort_field",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sort order",
+                        "description": "Sort order (deprecated, use sort)",
                         "name": "sort_order",
                         "in": "query"
                     },
@@ -443,32 +490,77 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "description": "Offset of projects",
-                        "name": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from the Link header",
+                        "name": "cursor",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Technology",
+                        "description": "Project",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Technology"
+                                "$ref": "#/definitions/models.Project"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to first, prev, next and last pages"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, absent on the last page"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of projects"
                             }
                         }
                     },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "400": {
                         "description": "Bad request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Create technology and write to database",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create project and write to database",
                 "consumes": [
                     "application/json"
                 ],
@@ -478,11 +570,11 @@ const docTemplate = `{
                 "tags": [
                     "Portfolio"
                 ],
-                "summary": "Create Technology",
+                "summary": "Create Project",
                 "parameters": [
                     {
-                        "description": "Technology name",
-                        "name": "name",
+                        "description": "Project title",
+                        "name": "title",
                         "in": "body",
                         "required": true,
                         "schema": {
@@ -490,35 +582,117 @@ const docTemplate = `{
                         }
                     },
                     {
-                        "description": "Technology svg",
-                        "name": "svg",
+                        "description": "Project version, recorded as its first release",
+                        "name": "version",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Project description",
+                        "name": "description",
                         "in": "body",
+                        "required": true,
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    {
+                        "description": "Technology ID",
+                        "name": "tech_id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    {
+                        "description": "Is active",
+                        "name": "isActive",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    {
+                        "description": "Is archived",
+                        "name": "isArchived",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    {
+                        "description": "Is developing",
+                        "name": "isDeveloping",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    {
+                        "description": "Links",
+                        "name": "links",
+                        "in": "body",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Technology ID",
+                        "description": "Project ID",
                         "schema": {
                             "type": "integer"
                         }
                     },
                     "400": {
                         "description": "Bad request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
             }
         },
-        "/portfolio/techs/{id}": {
+        "/portfolio/projects/{id}": {
             "get": {
-                "description": "Get technology",
+                "description": "Get project",
                 "consumes": [
                     "application/json"
                 ],
@@ -528,39 +702,78 @@ const docTemplate = `{
                 "tags": [
                     "Portfolio"
                 ],
-                "summary": "Technology",
+                "summary": "Project",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Technology ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of a cached response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Technology",
+                        "description": "Project",
                         "schema": {
-                            "$ref": "#/definitions/models.Technology"
+                            "$ref": "#/definitions/models.Project"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last updated"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "400": {
                         "description": "Bad request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "404": {
-                        "description": "Technology not found",
-                        "schema": {}
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
             },
             "delete": {
-                "description": "Delete technology",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Move project to the trash together with its assets",
                 "consumes": [
                     "application/json"
                 ],
@@ -570,11 +783,11 @@ const docTemplate = `{
                 "tags": [
                     "Portfolio"
                 ],
-                "summary": "Delete Technology",
+                "summary": "Delete Project",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Technology ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -590,20 +803,46 @@ const docTemplate = `{
                     },
                     "400": {
                         "description": "Bad request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "404": {
-                        "description": "Technology not found",
-                        "schema": {}
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     }
                 }
             },
             "patch": {
-                "description": "Update technology",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update project",
                 "consumes": [
                     "application/json"
                 ],
@@ -613,26 +852,77 @@ const docTemplate = `{
                 "tags": [
                     "Portfolio"
                 ],
-                "summary": "Update Technology",
+                "summary": "Update Project",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Technology ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Technology name",
-                        "name": "name",
+                        "description": "Project title",
+                        "name": "title",
                         "in": "body",
                         "schema": {
                             "type": "string"
                         }
                     },
                     {
-                        "description": "Technology svg",
-                        "name": "svg",
+                        "description": "Released version, added to the releases; the project version stays at the latest release",
+                        "name": "version",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Project description",
+                        "name": "description",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Technology ID",
+                        "name": "tech_id",
+                        "in": "body",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    {
+                        "description": "Is active",
+                        "name": "isActive",
+                        "in": "body",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    {
+                        "description": "Is archived",
+                        "name": "isArchived",
+                        "in": "body",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    {
+                        "description": "Is developing",
+                        "name": "isDeveloping",
+                        "in": "body",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    {
+                        "description": "Links",
+                        "name": "links",
                         "in": "body",
                         "schema": {
                             "type": "string"
@@ -649,27 +939,2288 @@ const docTemplate = `{
                     },
                     "400": {
                         "description": "Bad request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "404": {
                         "description": "Technology not found",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/projects/{id}/assets": {
+            "get": {
+                "description": "Get the assets of a project in order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Project asset list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Assets",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProjectAsset"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload a screenshot or another asset of a project. The type is detected from the content",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Upload project asset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Asset file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Caption",
+                        "name": "caption",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position to insert the asset at, the end by default",
+                        "name": "position",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Asset",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProjectAsset"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Asset too large",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Asset type not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/projects/{id}/assets/{assetId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete an asset and its file",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Delete project asset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Asset not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change the caption of an asset or move it to another position",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Update project asset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Asset update",
+                        "name": "asset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AssetUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Asset",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProjectAsset"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Asset not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/projects/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the revisions of a project, newest first, with who made them and the fields they changed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Project history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revisions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Revision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/projects/{id}/history/{revisionId}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Bring a project back to the state it had after a revision",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Revert Project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision ID",
+                        "name": "revisionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reverted project",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Revision deleted the project",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Revision is no longer valid",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/projects/{id}/releases": {
+            "get": {
+                "description": "Get the releases of a project, latest version first unless sorted otherwise. Versions are compared by semver precedence",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Project release list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lowest version listed",
+                        "name": "min_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highest version listed",
+                        "name": "max_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only pre-releases when true, only releases when false",
+                        "name": "prerelease",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of releases",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of releases",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from the Link header",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Releases",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Release"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to first, prev, next and last pages"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, absent on the last page"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of releases"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a release to a project. The latest release by semver precedence is the version of the project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Create project release",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Release",
+                        "name": "release",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Release"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Release",
+                        "schema": {
+                            "$ref": "#/definitions/models.Release"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Version already released",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/projects/{id}/releases/{releaseId}": {
+            "get": {
+                "description": "Get a release of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Project release",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Release ID",
+                        "name": "releaseId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Release",
+                        "schema": {
+                            "$ref": "#/definitions/models.Release"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Release not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a release of a project. The project keeps its version when the last release is deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Delete project release",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Release ID",
+                        "name": "releaseId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Release not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change a release of a project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Update project release",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Release ID",
+                        "name": "releaseId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Release update",
+                        "name": "release",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReleaseUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Release",
+                        "schema": {
+                            "$ref": "#/definitions/models.Release"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Release not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Version already released",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/projects/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Take project out of the trash together with its technology links and assets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Restore Project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored project",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/techs": {
+            "get": {
+                "description": "Get technology list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Technology list",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "description": "Technology ID",
+                        "name": "tech_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field (deprecated, use sort)",
+                        "name": "sort_field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order (deprecated, use sort)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of technologies",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of technologies",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from the Link header",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Technology",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Technology"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to first, prev, next and last pages"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, absent on the last page"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of technologies"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create technology and write to database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Create Technology",
+                "parameters": [
+                    {
+                        "description": "Technology name",
+                        "name": "name",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Technology svg",
+                        "name": "svg",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Technology ID",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/techs/stats": {
+            "get": {
+                "description": "Get the number of projects using each technology by status and the technologies most often used together with it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Technology statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of co-occurring technologies per technology, 5 by default, at most 50",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Technology statistics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TechnologyStats"
+                            }
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching policy"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/techs/{id}": {
+            "get": {
+                "description": "Get technology",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Technology",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Technology ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of a cached response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Technology",
+                        "schema": {
+                            "$ref": "#/definitions/models.Technology"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last updated"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Technology not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Move technology to the trash. Projects stop listing it until it is restored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Delete Technology",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Technology ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Technology not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update technology",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Update Technology",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Technology ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Technology name",
+                        "name": "name",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Technology svg",
+                        "name": "svg",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Technology not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/techs/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the revisions of a technology, newest first, with who made them and the fields they changed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Technology history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Technology ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revisions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Revision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Technology not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/techs/{id}/history/{revisionId}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Bring a technology back to the state it had after a revision",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Revert Technology",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Technology ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision ID",
+                        "name": "revisionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reverted technology",
+                        "schema": {
+                            "$ref": "#/definitions/models.Technology"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Technology or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Revision deleted the technology",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Revision is no longer valid",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/techs/{id}/icon.svg": {
+            "get": {
+                "description": "Get the sanitized SVG icon of a technology",
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Technology icon",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Technology ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached icon",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SVG icon",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching policy"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the icon"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Technology or icon not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/techs/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Take technology out of the trash together with its links to projects",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Restore Technology",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Technology ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored technology",
+                        "schema": {
+                            "$ref": "#/definitions/models.Technology"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Technology not found in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/trash/projects": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the projects in the trash",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Project trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Full-text search over title, description and technology names",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "description": "Technology ID",
+                        "name": "tech_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of projects",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of projects",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from the Link header",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Project"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to first, prev, next and last pages"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, absent on the last page"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of projects"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/trash/techs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the technologies in the trash",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Technology trash",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "description": "Technology ID",
+                        "name": "tech_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of technologies",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of technologies",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from the Link header",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Technology",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Technology"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to first, prev, next and last pages"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, absent on the last page"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of technologies"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apperrors.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "quota": {
+                    "description": "Quota is the number of requests allowed per quota window, 0 means\nunlimited.",
+                    "type": "integer"
+                },
+                "quota_used": {
+                    "type": "integer"
+                },
+                "quota_window_start": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Scope"
+                    }
+                }
+            }
+        },
+        "models.APIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quota": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.Scope"
+                    }
+                }
+            }
+        },
+        "models.Actor": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "api_key_name": {
+                    "type": "string"
+                },
+                "user_email": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AssetUpdate": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.Bundle": {
+            "type": "object",
+            "properties": {
+                "exported_at": {
+                    "type": "string"
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BundleProject"
+                    }
+                },
+                "technologies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BundleTechnology"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BundleProject": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_archived": {
+                    "type": "boolean"
+                },
+                "is_developing": {
+                    "type": "boolean"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "technologies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BundleTechnology": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "svg": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "quota": {
+                    "description": "Quota is the number of requests allowed per quota window, 0 means\nunlimited.",
+                    "type": "integer"
+                },
+                "quota_used": {
+                    "type": "integer"
+                },
+                "quota_window_start": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Scope"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
+        "models.Credentials": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Project": {
             "type": "object",
+            "required": [
+                "isActive",
+                "isArchived",
+                "isDeveloping",
+                "title",
+                "version"
+            ],
             "properties": {
-                "dscription": {
+                "assets": {
+                    "description": "Assets are managed through the asset endpoints, never by the project\nbody.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProjectAsset"
+                    }
+                },
+                "created_at": {
+                    "description": "CreatedAt and UpdatedAt are maintained by the repository. UpdatedAt\nalso changes when the linked technologies or the assets change.",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt is only set on projects in the trash.",
                     "type": "string"
                 },
+                "dscription": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -684,10 +3235,32 @@ const docTemplate = `{
                 },
                 "links": {
                     "type": "array",
+                    "maxItems": 20,
                     "items": {
                         "type": "string"
                     }
                 },
+                "search": {
+                    "description": "Search is only set on projects listed with a search query.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SearchResult"
+                        }
+                    ]
+                },
+                "slug": {
+                    "description": "Slug names the project in page URLs. It is derived from the title\nwhen the project is created without one and kept when the title\nchanges.",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "tech_id": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "technologies": {
                     "type": "array",
                     "items": {
@@ -695,6 +3268,114 @@ const docTemplate = `{
                     }
                 },
                 "title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProjectAsset": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Release": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "artifacts": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "description": "Notes are markdown.",
+                    "type": "string",
+                    "maxLength": 20000
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "released_at": {
+                    "description": "ReleasedAt defaults to the time the release is created.",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReleaseUpdate": {
+            "type": "object",
+            "properties": {
+                "artifacts": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 20000
+                },
+                "released_at": {
                     "type": "string"
                 },
                 "version": {
@@ -702,7 +3383,131 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Revision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.RevisionAction"
+                },
+                "actor": {
+                    "$ref": "#/definitions/models.Actor"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "description": "Before and After are snapshots of the record, see ProjectSnapshot and\nTechnologySnapshot. Before is null for creates and restores, After\nfor deletes.",
+                    "type": "object"
+                },
+                "changes": {
+                    "description": "Changes are the fields that differ between Before and After.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity": {
+                    "$ref": "#/definitions/models.RevisionEntity"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RevisionAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "restore",
+                "revert"
+            ],
+            "x-enum-varnames": [
+                "RevisionCreate",
+                "RevisionUpdate",
+                "RevisionDelete",
+                "RevisionRestore",
+                "RevisionRevert"
+            ]
+        },
+        "models.RevisionEntity": {
+            "type": "string",
+            "enum": [
+                "project",
+                "technology"
+            ],
+            "x-enum-varnames": [
+                "RevisionProject",
+                "RevisionTechnology"
+            ]
+        },
+        "models.Scope": {
+            "type": "string",
+            "enum": [
+                "projects:read",
+                "projects:write",
+                "techs:read",
+                "techs:write"
+            ],
+            "x-enum-varnames": [
+                "ScopeProjectsRead",
+                "ScopeProjectsWrite",
+                "ScopeTechsRead",
+                "ScopeTechsWrite"
+            ]
+        },
+        "models.SearchResult": {
+            "type": "object",
+            "properties": {
+                "description_highlight": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "title_highlight": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Technology": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "created_at": {
+                    "description": "CreatedAt and UpdatedAt are maintained by the repository.",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt is only set on technologies in the trash.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "svg": {
+                    "type": "string",
+                    "maxLength": 65536
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TechnologyCooccurrence": {
             "type": "object",
             "properties": {
                 "id": {
@@ -711,11 +3516,124 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "projects": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TechnologyStats": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "type": "integer"
+                },
+                "archived": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "CreatedAt and UpdatedAt are maintained by the repository.",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt is only set on technologies in the trash.",
+                    "type": "string"
+                },
+                "developing": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "projects": {
+                    "type": "integer"
+                },
                 "svg": {
+                    "type": "string",
+                    "maxLength": 65536
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "used_with": {
+                    "description": "UsedWith lists the technologies most often used in the same projects.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TechnologyCooccurrence"
+                    }
+                }
+            }
+        },
+        "models.TokenPair": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors lists every invalid field of a validation problem.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apperrors.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Scoped API key as \"ApiKey \u003ckey\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Access token from /auth/login as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
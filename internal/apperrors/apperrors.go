@@ -0,0 +1,130 @@
+// Package apperrors defines the domain errors shared by the repository,
+// service and transport layers.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Use errors.Is to test which kind an error belongs to.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+	ErrForeignKey = errors.New("foreign key violation")
+	ErrBadRequest = errors.New("bad request")
+	ErrTimeout    = errors.New("timeout")
+	ErrTooLarge   = errors.New("too large")
+	ErrMediaType  = errors.New("unsupported media type")
+
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// Error is a domain error with a stable machine readable code and a message
+// that is safe to show to API clients.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error
+	// Fields lists the individual problems of a validation error.
+	Fields []FieldError
+}
+
+// FieldError is a problem with a single field of a request. Field is the
+// JSON path of the field, e.g. "links[1]".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// Wrap returns a copy of e caused by err.
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+func newError(kind error, code, format string, args ...any) *Error {
+	return &Error{Kind: kind, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func NotFound(code, format string, args ...any) *Error {
+	return newError(ErrNotFound, code, format, args...)
+}
+
+func Conflict(code, format string, args ...any) *Error {
+	return newError(ErrConflict, code, format, args...)
+}
+
+func Validation(code, format string, args ...any) *Error {
+	return newError(ErrValidation, code, format, args...)
+}
+
+// InvalidFields returns a validation error listing fields, or nil when there
+// are none.
+func InvalidFields(fields []FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	err := Validation("validation_failed", "Request has %d invalid field(s)", len(fields))
+	err.Fields = fields
+	return err
+}
+
+func ForeignKey(code, format string, args ...any) *Error {
+	return newError(ErrForeignKey, code, format, args...)
+}
+
+func BadRequest(code, format string, args ...any) *Error {
+	return newError(ErrBadRequest, code, format, args...)
+}
+
+func Timeout(code, format string, args ...any) *Error {
+	return newError(ErrTimeout, code, format, args...)
+}
+
+func TooLarge(code, format string, args ...any) *Error {
+	return newError(ErrTooLarge, code, format, args...)
+}
+
+func MediaType(code, format string, args ...any) *Error {
+	return newError(ErrMediaType, code, format, args...)
+}
+
+func Unauthorized(code, format string, args ...any) *Error {
+	return newError(ErrUnauthorized, code, format, args...)
+}
+
+func Forbidden(code, format string, args ...any) *Error {
+	return newError(ErrForbidden, code, format, args...)
+}
+
+func RateLimited(code, format string, args ...any) *Error {
+	return newError(ErrRateLimited, code, format, args...)
+}
+
+// As returns the domain error in err's chain, if any.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
@@ -1,15 +1,30 @@
 package config
 
 import (
+	"gowebsite/internal/service"
+	"gowebsite/internal/site"
 	"gowebsite/pkg/db/postgres"
+	"gowebsite/pkg/storage"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
 	postgres.PostgresConfig
+	postgres.MigrateConfig
+	storage.StorageConfig
+	service.AuthConfig
+	service.APIKeyConfig
+	service.AssetConfig
+	site.SiteConfig
 	RESTServerPort string `env:"REST_SERVER_PORT" envDefault:"8080"`
 	RESTServerHost string `env:"REST_SERVER_HOST" envDefault:"localhost"`
+
+	// Per-route deadlines applied to the request context: reads cover GET
+	// routes, writes cover POST/PATCH/DELETE routes.
+	RESTReadTimeout  time.Duration `env:"REST_READ_TIMEOUT" env-default:"5s"`
+	RESTWriteTimeout time.Duration `env:"REST_WRITE_TIMEOUT" env-default:"15s"`
 }
 
 func New(path string) *Config {
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Scope grants an API key access to a group of routes.
+type Scope string
+
+const (
+	ScopeProjectsRead  Scope = "projects:read"
+	ScopeProjectsWrite Scope = "projects:write"
+	ScopeTechsRead     Scope = "techs:read"
+	ScopeTechsWrite    Scope = "techs:write"
+)
+
+var Scopes = []Scope{ScopeProjectsRead, ScopeProjectsWrite, ScopeTechsRead, ScopeTechsWrite}
+
+func (s Scope) IsValid() bool {
+	return slices.Contains(Scopes, s)
+}
+
+func (s Scope) IsRead() bool {
+	return s == ScopeProjectsRead || s == ScopeTechsRead
+}
+
+type APIKey struct {
+	ID     int64   `json:"id" db:"id"`
+	Name   string  `json:"name" db:"name"`
+	Prefix string  `json:"prefix" db:"prefix"`
+	Scopes []Scope `json:"scopes" db:"scopes"`
+	// Quota is the number of requests allowed per quota window, 0 means
+	// unlimited.
+	Quota            int        `json:"quota" db:"quota"`
+	QuotaUsed        int        `json:"quota_used" db:"quota_used"`
+	QuotaWindowStart time.Time  `json:"quota_window_start" db:"quota_window_start"`
+	ExpiresAt        *time.Time `json:"expires_at" db:"expires_at"`
+	LastUsedAt       *time.Time `json:"last_used_at" db:"last_used_at"`
+	CreatedBy        *int64     `json:"created_by" db:"created_by"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt        *time.Time `json:"revoked_at" db:"revoked_at"`
+	KeyHash          string     `json:"-" db:"key_hash"`
+}
+
+type APIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []Scope    `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Quota     *int       `json:"quota"`
+}
+
+// CreatedAPIKey is returned once, on creation; only the hash of Key is kept.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// QuotaStatus is the state of an API key quota after a request was counted.
+type QuotaStatus struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
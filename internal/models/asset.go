@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// ProjectAsset is an uploaded file of a project such as a screenshot.
+// Assets of a project are ordered by Position, starting from 0.
+type ProjectAsset struct {
+	ID          int64     `json:"id" db:"id"`
+	ProjectID   int64     `json:"project_id" db:"project_id"`
+	URL         string    `json:"url" db:"-"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	Caption     string    `json:"caption" db:"caption"`
+	Position    int       `json:"position" db:"position"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	// StorageKey is where the file is kept, see storage.Storage.
+	StorageKey string `json:"-" db:"storage_key"`
+}
+
+// AssetUpload is a file uploaded as a project asset.
+type AssetUpload struct {
+	Filename string
+	Size     int64
+	Caption  string `validate:"max=500"`
+	// Position to insert the asset at, the end when nil.
+	Position *int `validate:"omitempty,min=0"`
+}
+
+type AssetUpdate struct {
+	Caption  *string `json:"caption" validate:"omitempty,max=500"`
+	Position *int    `json:"position" validate:"omitempty,min=0"`
+}
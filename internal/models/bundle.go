@@ -0,0 +1,211 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleVersion is the version of the bundle format written by exports.
+// Imports reject bundles of other versions.
+const BundleVersion = 1
+
+// Bundle is a portfolio export. Projects refer to technologies by name, so
+// a bundle can be imported into a database with different ids.
+type Bundle struct {
+	Version      int                 `json:"version" yaml:"version"`
+	ExportedAt   time.Time           `json:"exported_at" yaml:"exported_at"`
+	Technologies []*BundleTechnology `json:"technologies" yaml:"technologies"`
+	Projects     []*BundleProject    `json:"projects" yaml:"projects"`
+}
+
+// BundleTechnology is a technology in a bundle. ID is the id in the
+// exporting database, kept for reference only.
+type BundleTechnology struct {
+	ID   int64   `json:"id,omitempty" yaml:"id,omitempty"`
+	Name string  `json:"name" yaml:"name"`
+	Svg  *string `json:"svg,omitempty" yaml:"svg,omitempty"`
+}
+
+// BundleProject is a project in a bundle. Technologies are technology
+// names. Without a slug, new projects derive theirs from the title and
+// existing ones keep theirs.
+type BundleProject struct {
+	ID           int64    `json:"id,omitempty" yaml:"id,omitempty"`
+	Title        string   `json:"title" yaml:"title"`
+	Slug         string   `json:"slug,omitempty" yaml:"slug,omitempty"`
+	Version      string   `json:"version" yaml:"version"`
+	Description  string   `json:"description" yaml:"description"`
+	IsActive     bool     `json:"is_active" yaml:"is_active"`
+	IsArchived   bool     `json:"is_archived" yaml:"is_archived"`
+	IsDeveloping bool     `json:"is_developing" yaml:"is_developing"`
+	Links        []string `json:"links" yaml:"links"`
+	Technologies []string `json:"technologies" yaml:"technologies"`
+}
+
+// BundleFormat is the encoding of a bundle.
+type BundleFormat string
+
+const (
+	BundleJSON BundleFormat = "json"
+	BundleYAML BundleFormat = "yaml"
+)
+
+func (f BundleFormat) IsValid() bool {
+	return f == BundleJSON || f == BundleYAML
+}
+
+// ImportMode selects what happens to records missing from a bundle.
+type ImportMode string
+
+const (
+	// ImportMerge creates and updates the records of the bundle and leaves
+	// the others alone.
+	ImportMerge ImportMode = "merge"
+	// ImportReplace also moves the records missing from the bundle to the
+	// trash.
+	ImportReplace ImportMode = "replace"
+)
+
+func (m ImportMode) IsValid() bool {
+	return m == ImportMerge || m == ImportReplace
+}
+
+type ImportOptions struct {
+	Mode ImportMode
+	// DryRun reports what an import would do without changing anything.
+	DryRun bool
+}
+
+// ImportReport lists what an import did, or with DryRun would do, by
+// technology name and project title.
+type ImportReport struct {
+	Mode         ImportMode    `json:"mode" yaml:"mode"`
+	DryRun       bool          `json:"dry_run" yaml:"dry_run"`
+	Technologies *ImportResult `json:"technologies" yaml:"technologies"`
+	Projects     *ImportResult `json:"projects" yaml:"projects"`
+}
+
+type ImportResult struct {
+	Created   []string `json:"created" yaml:"created"`
+	Updated   []string `json:"updated" yaml:"updated"`
+	Unchanged []string `json:"unchanged" yaml:"unchanged"`
+	Deleted   []string `json:"deleted" yaml:"deleted"`
+}
+
+// NewImportReport sums up the plan of an import.
+func NewImportReport(plan *Plan, opts ImportOptions) *ImportReport {
+	report := &ImportReport{
+		Mode:         opts.Mode,
+		DryRun:       opts.DryRun,
+		Technologies: newImportResult(),
+		Projects:     newImportResult(),
+	}
+	for _, step := range plan.Steps {
+		result := report.Projects
+		if step.Entity == RevisionTechnology {
+			result = report.Technologies
+		}
+		switch step.Action {
+		case PlanCreate:
+			result.Created = append(result.Created, step.Name)
+		case PlanUpdate:
+			result.Updated = append(result.Updated, step.Name)
+		case PlanKeep:
+			result.Unchanged = append(result.Unchanged, step.Name)
+		case PlanDelete:
+			result.Deleted = append(result.Deleted, step.Name)
+		}
+	}
+	return report
+}
+
+func newImportResult() *ImportResult {
+	return &ImportResult{Created: []string{}, Updated: []string{}, Unchanged: []string{}, Deleted: []string{}}
+}
+
+// PlanAction is what applying a bundle does to a record.
+type PlanAction string
+
+const (
+	PlanCreate PlanAction = "create"
+	PlanUpdate PlanAction = "update"
+	PlanDelete PlanAction = "delete"
+	// PlanKeep leaves a record of the bundle as it is.
+	PlanKeep PlanAction = "keep"
+)
+
+// Plan is the difference between a bundle and the database, technologies
+// first, in the order they are applied.
+type Plan struct {
+	Steps []*PlanStep `json:"steps"`
+}
+
+// PlanStep is the change of one technology or project, named by its name
+// or title. Changes lists the fields of an update in their bundle form, so
+// technologies of projects appear by name.
+type PlanStep struct {
+	Entity  RevisionEntity `json:"entity"`
+	Action  PlanAction     `json:"action"`
+	Name    string         `json:"name"`
+	Changes []*FieldChange `json:"changes,omitempty"`
+}
+
+// Count returns the number of steps with action.
+func (p *Plan) Count(action PlanAction) int {
+	n := 0
+	for _, step := range p.Steps {
+		if step.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Empty reports whether applying the plan changes nothing.
+func (p *Plan) Empty() bool {
+	return p.Count(PlanKeep) == len(p.Steps)
+}
+
+type BundleQuery struct {
+	// Format is BundleJSON by default.
+	Format BundleFormat `form:"format"`
+}
+
+// EncodeBundle writes bundle to w in format.
+func EncodeBundle(w io.Writer, bundle *Bundle, format BundleFormat) error {
+	if format == BundleYAML {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(bundle); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bundle)
+}
+
+// DecodeBundle reads a bundle in format from r. Unknown fields are
+// rejected, so a misspelled field doesn't silently drop data.
+func DecodeBundle(r io.Reader, format BundleFormat) (*Bundle, error) {
+	var bundle Bundle
+	if format == BundleYAML {
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		if err := dec.Decode(&bundle); err != nil {
+			return nil, fmt.Errorf("invalid YAML bundle: %w", err)
+		}
+		return &bundle, nil
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("invalid JSON bundle: %w", err)
+	}
+	return &bundle, nil
+}
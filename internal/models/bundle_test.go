@@ -0,0 +1,98 @@
+package models
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	svg := `<svg viewBox="0 0 1 1"></svg>`
+	bundle := &Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+		Technologies: []*BundleTechnology{
+			{ID: 1, Name: "Go", Svg: &svg},
+			{ID: 2, Name: "Rust"},
+		},
+		Projects: []*BundleProject{{
+			ID:           3,
+			Title:        "Site: \"portfolio\"",
+			Slug:         "site",
+			Version:      "1.2.0-rc.1",
+			Description:  "Line one\nline two",
+			IsActive:     true,
+			Links:        []string{"https://example.com"},
+			Technologies: []string{"Go", "Rust"},
+		}},
+	}
+	for _, format := range []BundleFormat{BundleJSON, BundleYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeBundle(&buf, bundle, format); err != nil {
+				t.Fatalf("EncodeBundle() error = %v", err)
+			}
+			got, err := DecodeBundle(&buf, format)
+			if err != nil {
+				t.Fatalf("DecodeBundle() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, bundle) {
+				t.Errorf("round trip = %+v, want %+v", got, bundle)
+			}
+		})
+	}
+}
+
+func TestDecodeBundleRejectsUnknownFields(t *testing.T) {
+	tests := []struct {
+		format BundleFormat
+		src    string
+	}{
+		{BundleJSON, `{"version": 1, "projects": [{"title": "Site", "tittle": "Typo"}]}`},
+		{BundleYAML, "version: 1\nprojects:\n  - title: Site\n    tittle: Typo\n"},
+		{BundleJSON, `{"version": 1, "technologies": "Go"}`},
+		{BundleYAML, "version: [1\n"},
+	}
+	for _, tt := range tests {
+		if _, err := DecodeBundle(strings.NewReader(tt.src), tt.format); err == nil {
+			t.Errorf("DecodeBundle(%s, %q) succeeded, want error", tt.format, tt.src)
+		}
+	}
+}
+
+func TestNewImportReport(t *testing.T) {
+	plan := &Plan{Steps: []*PlanStep{
+		{Entity: RevisionTechnology, Action: PlanCreate, Name: "Rust"},
+		{Entity: RevisionTechnology, Action: PlanKeep, Name: "Go"},
+		{Entity: RevisionTechnology, Action: PlanDelete, Name: "PHP"},
+		{Entity: RevisionProject, Action: PlanUpdate, Name: "Site"},
+		{Entity: RevisionProject, Action: PlanCreate, Name: "New"},
+	}}
+	report := NewImportReport(plan, ImportOptions{Mode: ImportReplace, DryRun: true})
+
+	if report.Mode != ImportReplace || !report.DryRun {
+		t.Errorf("report mode = %s, dry run = %v", report.Mode, report.DryRun)
+	}
+	want := map[string][2][]string{
+		"created":   {{"Rust"}, {"New"}},
+		"updated":   {{}, {"Site"}},
+		"unchanged": {{"Go"}, {}},
+		"deleted":   {{"PHP"}, {}},
+	}
+	got := map[string][2][]string{
+		"created":   {report.Technologies.Created, report.Projects.Created},
+		"updated":   {report.Technologies.Updated, report.Projects.Updated},
+		"unchanged": {report.Technologies.Unchanged, report.Projects.Unchanged},
+		"deleted":   {report.Technologies.Deleted, report.Projects.Deleted},
+	}
+	for key, lists := range want {
+		for i, entity := range []string{"technologies", "projects"} {
+			if !slices.Equal(got[key][i], lists[i]) || got[key][i] == nil {
+				t.Errorf("%s %s = %q, want %q", entity, key, got[key][i], lists[i])
+			}
+		}
+	}
+}
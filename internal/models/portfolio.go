@@ -1,44 +1,136 @@
 package models
 
 import (
+	"time"
+
 	"github.com/volatiletech/null/v9"
 )
 
-// Technology model
+// Technology model. The validate tags are enforced by the validation package.
 type Technology struct {
-	ID   int64       `form:"id" json:"id" db:"id"`
-	Name string      `form:"name" json:"name" db:"name"`
-	Svg  null.String `form:"svg" json:"svg" db:"svg" swaggertype:"string"`
+	ID   int64       `form:"id" json:"id" db:"id" validate:"-"`
+	Name string      `form:"name" json:"name" db:"name" validate:"required,max=100"`
+	Svg  null.String `form:"svg" json:"svg" db:"svg" swaggertype:"string" validate:"omitempty,max=65536,svg"`
+	// CreatedAt and UpdatedAt are maintained by the repository.
+	CreatedAt time.Time `form:"-" json:"created_at" db:"created_at" validate:"-"`
+	UpdatedAt time.Time `form:"-" json:"updated_at" db:"updated_at" validate:"-"`
+	// DeletedAt is only set on technologies in the trash.
+	DeletedAt *time.Time `form:"-" json:"deleted_at,omitempty" db:"deleted_at" validate:"-"`
 }
 
+// Project model. Besides the validate tags, IsActive and IsArchived are
+// mutually exclusive and TechnologyIDs must exist.
 type Project struct {
-	ID            int64         `form:"id" json:"id" db:"id"`
-	Title         string        `form:"title" json:"title" db:"title"`
-	Version       string        `form:"version" json:"version" db:"version"`
-	Description   string        `form:"dscription" json:"dscription" db:"description"`
-	TechnologyIDs []int64       `form:"tech_id" json:"tech_id" db:"-"`
-	Technologies  []*Technology `form:"technologies" json:"technologies" db:"-"`
-	IsActive      null.Bool     `form:"isActive" json:"isActive" db:"is_active" swaggertype:"boolean"`
-	IsArchived    null.Bool     `form:"isArchived" json:"isArchived" db:"is_archived" swaggertype:"boolean"`
-	IsDeveloping  null.Bool     `form:"isDeveloping" json:"isDeveloping" db:"is_developing" swaggertype:"boolean"`
-	Links         []string      `form:"links" json:"links" db:"links"`
+	ID            int64         `form:"id" json:"id" db:"id" validate:"-"`
+	Title         string        `form:"title" json:"title" db:"title" validate:"required,max=200"`
+	Version       string        `form:"version" json:"version" db:"version" validate:"required,semver"`
+	Description   string        `form:"dscription" json:"dscription" db:"description" validate:"max=5000"`
+	TechnologyIDs []int64       `form:"tech_id" json:"tech_id" db:"-" validate:"max=50,unique,dive,gt=0"`
+	Technologies  []*Technology `form:"technologies" json:"technologies" db:"-" validate:"-"`
+	IsActive      null.Bool     `form:"isActive" json:"isActive" db:"is_active" swaggertype:"boolean" validate:"required"`
+	IsArchived    null.Bool     `form:"isArchived" json:"isArchived" db:"is_archived" swaggertype:"boolean" validate:"required"`
+	IsDeveloping  null.Bool     `form:"isDeveloping" json:"isDeveloping" db:"is_developing" swaggertype:"boolean" validate:"required"`
+	Links         []string      `form:"links" json:"links" db:"links" validate:"max=20,dive,max=2048,http_url"`
+	// Slug names the project in page URLs. It is derived from the title
+	// when the project is created without one and kept when the title
+	// changes.
+	Slug string `form:"slug" json:"slug" db:"slug" validate:"omitempty,max=100,slug"`
+	// Assets are managed through the asset endpoints, never by the project
+	// body.
+	Assets []*ProjectAsset `form:"-" json:"assets" db:"-" validate:"-"`
+	// CreatedAt and UpdatedAt are maintained by the repository. UpdatedAt
+	// also changes when the linked technologies or the assets change.
+	CreatedAt time.Time `form:"-" json:"created_at" db:"created_at" validate:"-"`
+	UpdatedAt time.Time `form:"-" json:"updated_at" db:"updated_at" validate:"-"`
+	// VersionKey orders Version by semver precedence. Version itself is
+	// the latest release; setting it adds that release when it is missing.
+	VersionKey string `form:"-" json:"-" db:"version_key" validate:"-"`
+	// DeletedAt is only set on projects in the trash.
+	DeletedAt *time.Time `form:"-" json:"deleted_at,omitempty" db:"deleted_at" validate:"-"`
+	// Search is only set on projects listed with a search query.
+	Search *SearchResult `form:"-" json:"search,omitempty" db:"-" validate:"-"`
+}
+
+// SearchResult is how a project matched a full-text search. The highlights
+// wrap the matched words in <mark> tags; the rest of the text is not
+// escaped.
+type SearchResult struct {
+	Rank                 float64 `json:"rank"`
+	TitleHighlight       string  `json:"title_highlight"`
+	DescriptionHighlight string  `json:"description_highlight"`
+}
+
+// TechMatch selects how a project filter matches technologies.
+type TechMatch string
+
+const (
+	// TechMatchAny matches projects using at least one of the technologies.
+	TechMatchAny TechMatch = "any"
+	// TechMatchAll matches projects using every one of the technologies.
+	TechMatchAll TechMatch = "all"
+	// TechMatchNone matches projects using none of the technologies.
+	TechMatchNone TechMatch = "none"
+)
+
+func (m TechMatch) IsValid() bool {
+	return m == TechMatchAny || m == TechMatchAll || m == TechMatchNone
 }
 
 type ProjectFilter struct {
+	// Q is a full-text search query in web search syntax: words, "quoted
+	// phrases", "or" and -excluded words.
+	Q              string   `form:"q" db:"-"`
 	TechnologiesID *[]int64 `form:"tech_id" db:"tech_id"`
-	IsActive       *bool    `form:"is_active" db:"is_active"`
-	IsArchived     *bool    `form:"is_archived" db:"is_archived"`
-	IsDeveloping   *bool    `form:"is_developing" db:"is_developing"`
-	SortField      string   `form:"sort_field" db:"sort_field"`
-	SortOrder      string   `form:"sort_order" db:"sort_order"`
-	Limit          uint64   `form:"limit" db:"limit"`
-	Offset         uint64   `form:"offset" db:"offset"`
+	// TechMatch is how TechnologiesID is matched, TechMatchAny by default.
+	TechMatch TechMatch `form:"tech_match" db:"-"`
+	// ExcludeTechnologiesID drops projects using any of these technologies.
+	ExcludeTechnologiesID *[]int64 `form:"exclude_tech_id" db:"-"`
+	IsActive              *bool    `form:"is_active" db:"is_active"`
+	IsArchived            *bool    `form:"is_archived" db:"is_archived"`
+	IsDeveloping          *bool    `form:"is_developing" db:"is_developing"`
+	// Sort is a comma separated list of fields, "-" prefix sorts descending.
+	Sort string `form:"sort" db:"-"`
+	// Deprecated: use Sort.
+	SortField string `form:"sort_field" db:"sort_field"`
+	// Deprecated: use Sort.
+	SortOrder string `form:"sort_order" db:"sort_order"`
+	Limit     uint64 `form:"limit" db:"limit"`
+	Offset    uint64 `form:"offset" db:"offset"`
+	// Cursor continues a keyset paginated list, see PageInfo.NextCursor.
+	Cursor string `form:"cursor" db:"-"`
+	// Deleted lists the trash instead of the live rows. It is set by the
+	// trash endpoints, never from the query string.
+	Deleted bool `form:"-" db:"-"`
 }
 
 type TechnologyFilter struct {
 	TechnologiesID *[]int64 `form:"tech_id" db:"tech_id"`
-	SortField      string   `form:"sort_field" db:"sort_field"`
-	SortOrder      string   `form:"sort_order" db:"sort_order"`
-	Limit          uint64   `form:"limit" db:"limit"`   //nolint:tagliatelle
-	Offset         uint64   `form:"offset" db:"offset"` //nolint:tagliatelle
+	// Sort is a comma separated list of fields, "-" prefix sorts descending.
+	Sort string `form:"sort" db:"-"`
+	// Deprecated: use Sort.
+	SortField string `form:"sort_field" db:"sort_field"`
+	// Deprecated: use Sort.
+	SortOrder string `form:"sort_order" db:"sort_order"`
+	Limit     uint64 `form:"limit" db:"limit"`   //nolint:tagliatelle
+	Offset    uint64 `form:"offset" db:"offset"` //nolint:tagliatelle
+	// Cursor continues a keyset paginated list, see PageInfo.NextCursor.
+	Cursor string `form:"cursor" db:"-"`
+	// Deleted lists the trash instead of the live rows. It is set by the
+	// trash endpoints, never from the query string.
+	Deleted bool `form:"-" db:"-"`
+}
+
+// PageInfo describes a page of a list. NextCursor is empty on the last page
+// and when the list is not limited.
+type PageInfo struct {
+	Total      int64
+	Limit      uint64
+	Offset     uint64
+	NextCursor string
+}
+
+// FeedFilter narrows a project feed to the projects using any of the
+// technologies.
+type FeedFilter struct {
+	TechnologiesID *[]int64 `form:"tech_id"`
 }
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Release is a released version of a project. The latest release by semver
+// precedence is the version of the project.
+type Release struct {
+	ID        int64  `json:"id" db:"id" validate:"-"`
+	ProjectID int64  `json:"project_id" db:"project_id" validate:"-"`
+	Version   string `json:"version" db:"version" validate:"required,semver"`
+	// VersionKey orders versions by semver precedence, see semver_key in
+	// the migrations.
+	VersionKey string `json:"-" db:"version_key" validate:"-"`
+	// ReleasedAt defaults to the time the release is created.
+	ReleasedAt time.Time `json:"released_at" db:"released_at" validate:"-"`
+	// Notes are markdown.
+	Notes     string    `json:"notes" db:"notes" validate:"max=20000"`
+	Artifacts []string  `json:"artifacts" db:"artifacts" validate:"max=20,dive,max=2048,http_url"`
+	CreatedAt time.Time `json:"created_at" db:"created_at" validate:"-"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" validate:"-"`
+}
+
+// ReleaseUpdate is a patch of a release; nil fields are left as they are.
+type ReleaseUpdate struct {
+	Version    *string    `json:"version" validate:"omitempty,semver"`
+	ReleasedAt *time.Time `json:"released_at" validate:"-"`
+	Notes      *string    `json:"notes" validate:"omitempty,max=20000"`
+	Artifacts  *[]string  `json:"artifacts" validate:"omitempty,max=20,dive,max=2048,http_url"`
+}
+
+type ReleaseFilter struct {
+	// MinVersion and MaxVersion bound the versions listed, inclusive, by
+	// semver precedence.
+	MinVersion string `form:"min_version" db:"-"`
+	MaxVersion string `form:"max_version" db:"-"`
+	// Prerelease lists only pre-releases when true, only releases when
+	// false.
+	Prerelease *bool `form:"prerelease" db:"-"`
+	// Sort is a comma separated list of fields, "-" prefix sorts descending.
+	Sort   string `form:"sort" db:"-"`
+	Limit  uint64 `form:"limit" db:"limit"`
+	Offset uint64 `form:"offset" db:"offset"`
+	// Cursor continues a keyset paginated list, see PageInfo.NextCursor.
+	Cursor string `form:"cursor" db:"-"`
+}
+
+func (f *ReleaseFilter) SortKeys() ([]SortKey, error) {
+	return ParseSort(f.Sort)
+}
+
+// ProjectRelease is a release together with the project it belongs to, as
+// the feeds list them.
+type ProjectRelease struct {
+	Release
+	ProjectTitle string
+	ProjectSlug  string
+	// Technologies are the names of the live technologies of the project.
+	Technologies []string
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/volatiletech/null/v9"
+)
+
+// RevisionEntity is the kind of record a revision belongs to.
+type RevisionEntity string
+
+const (
+	RevisionProject    RevisionEntity = "project"
+	RevisionTechnology RevisionEntity = "technology"
+)
+
+// RevisionAction is what a revision did to its record.
+type RevisionAction string
+
+const (
+	RevisionCreate  RevisionAction = "create"
+	RevisionUpdate  RevisionAction = "update"
+	RevisionDelete  RevisionAction = "delete"
+	RevisionRestore RevisionAction = "restore"
+	RevisionRevert  RevisionAction = "revert"
+)
+
+// Revision is one recorded change of a project or a technology.
+type Revision struct {
+	ID       int64          `json:"id" db:"id"`
+	Entity   RevisionEntity `json:"entity" db:"entity"`
+	EntityID int64          `json:"entity_id" db:"entity_id"`
+	Action   RevisionAction `json:"action" db:"action"`
+	Actor    Actor          `json:"actor" db:"-"`
+	// Before and After are snapshots of the record, see ProjectSnapshot and
+	// TechnologySnapshot. Before is null for creates and restores, After
+	// for deletes.
+	Before json.RawMessage `json:"before" db:"before" swaggertype:"object"`
+	After  json.RawMessage `json:"after" db:"after" swaggertype:"object"`
+	// Changes are the fields that differ between Before and After.
+	Changes   []*FieldChange `json:"changes" db:"-"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// Actor is who made a change. Both ids are empty for changes made outside
+// of a request, and become empty when the user or API key is deleted.
+type Actor struct {
+	UserID     *int64  `json:"user_id,omitempty"`
+	UserEmail  *string `json:"user_email,omitempty"`
+	APIKeyID   *int64  `json:"api_key_id,omitempty"`
+	APIKeyName *string `json:"api_key_name,omitempty"`
+}
+
+// FieldChange is the value of a snapshot field before and after a revision.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before" swaggertype:"object"`
+	After  json.RawMessage `json:"after" swaggertype:"object"`
+}
+
+// ProjectSnapshot is the part of a project its history records. Field
+// names match the project body so a revert can apply it as one.
+type ProjectSnapshot struct {
+	Title         string   `json:"title"`
+	Version       string   `json:"version"`
+	Description   string   `json:"dscription"`
+	TechnologyIDs []int64  `json:"tech_id"`
+	IsActive      bool     `json:"isActive"`
+	IsArchived    bool     `json:"isArchived"`
+	IsDeveloping  bool     `json:"isDeveloping"`
+	Links         []string `json:"links"`
+	// Slug is missing from snapshots taken before projects had slugs;
+	// reverting to them keeps the current slug.
+	Slug string `json:"slug,omitempty"`
+}
+
+// NewProjectSnapshot returns the snapshot of project. Technology ids are
+// sorted so their order never shows up as a change.
+func NewProjectSnapshot(project *Project) *ProjectSnapshot {
+	technologyIDs := slices.Clone(project.TechnologyIDs)
+	if technologyIDs == nil {
+		technologyIDs = []int64{}
+	}
+	slices.Sort(technologyIDs)
+	links := project.Links
+	if links == nil {
+		links = []string{}
+	}
+	return &ProjectSnapshot{
+		Title:         project.Title,
+		Version:       project.Version,
+		Description:   project.Description,
+		TechnologyIDs: technologyIDs,
+		IsActive:      project.IsActive.Bool,
+		IsArchived:    project.IsArchived.Bool,
+		IsDeveloping:  project.IsDeveloping.Bool,
+		Links:         links,
+		Slug:          project.Slug,
+	}
+}
+
+// Project returns the project with the id and the state of the snapshot.
+func (s *ProjectSnapshot) Project(id int64) *Project {
+	return &Project{
+		ID:            id,
+		Title:         s.Title,
+		Version:       s.Version,
+		Description:   s.Description,
+		TechnologyIDs: s.TechnologyIDs,
+		IsActive:      null.BoolFrom(s.IsActive),
+		IsArchived:    null.BoolFrom(s.IsArchived),
+		IsDeveloping:  null.BoolFrom(s.IsDeveloping),
+		Links:         s.Links,
+		Slug:          s.Slug,
+	}
+}
+
+// TechnologySnapshot is the part of a technology its history records.
+type TechnologySnapshot struct {
+	Name string      `json:"name"`
+	Svg  null.String `json:"svg" swaggertype:"string"`
+}
+
+func NewTechnologySnapshot(technology *Technology) *TechnologySnapshot {
+	return &TechnologySnapshot{Name: technology.Name, Svg: technology.Svg}
+}
+
+// Technology returns the technology with the id and the state of the
+// snapshot.
+func (s *TechnologySnapshot) Technology(id int64) *Technology {
+	return &Technology{ID: id, Name: s.Name, Svg: s.Svg}
+}
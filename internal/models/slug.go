@@ -0,0 +1,56 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultSlug is the slug of titles without a letter or digit to take one
+// from.
+const DefaultSlug = "project"
+
+// MaxSlugLen is the longest slug Slugify returns.
+const MaxSlugLen = 100
+
+// cyrillic transliterates Russian letters for slugs.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// Slugify derives a URL slug from a title: lowercase ASCII letters and
+// digits, Cyrillic transliterated, separated by single dashes.
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		var part string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		case cyrillic[r] != "":
+			part = cyrillic[r]
+		default:
+			if _, ok := cyrillic[r]; !ok {
+				dash = b.Len() > 0
+			}
+			continue
+		}
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteString(part)
+	}
+	slug := b.String()
+	if len(slug) > MaxSlugLen {
+		slug = strings.TrimRight(slug[:MaxSlugLen], "-")
+	}
+	if slug == "" {
+		return DefaultSlug
+	}
+	return slug
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortKey is one key of a multi-key sort.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+func (k SortKey) String() string {
+	if k.Desc {
+		return "-" + k.Field
+	}
+	return k.Field
+}
+
+// ParseSort parses a comma separated sort expression such as
+// "-is_active,title". A leading "-" sorts the key descending, a leading "+"
+// or no prefix sorts it ascending.
+func ParseSort(expr string) ([]SortKey, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{}
+		switch {
+		case strings.HasPrefix(part, "-"):
+			key.Desc = true
+			part = part[1:]
+		case strings.HasPrefix(part, "+"):
+			part = part[1:]
+		}
+		if part == "" {
+			return nil, fmt.Errorf("empty sort key in %q", expr)
+		}
+		key.Field = part
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// FormatSort is the inverse of ParseSort.
+func FormatSort(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// sortKeys merges the sort expression with the deprecated
+// sort_field/sort_order pair, which is used only when sort is empty.
+func sortKeys(sort, sortField, sortOrder string) ([]SortKey, error) {
+	if sort != "" || sortField == "" {
+		return ParseSort(sort)
+	}
+	key := SortKey{Field: sortField}
+	switch strings.ToLower(sortOrder) {
+	case "", "asc":
+	case "desc":
+		key.Desc = true
+	default:
+		return nil, fmt.Errorf("sort order must be asc or desc, got %q", sortOrder)
+	}
+	return []SortKey{key}, nil
+}
+
+// SortRelevance sorts search results by their rank.
+const SortRelevance = "relevance"
+
+// SortKeys returns the requested sort. Searches are sorted by descending
+// relevance unless asked otherwise; relevance can't be used without one.
+func (f *ProjectFilter) SortKeys() ([]SortKey, error) {
+	keys, err := sortKeys(f.Sort, f.SortField, f.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+	if f.Q == "" {
+		for _, key := range keys {
+			if key.Field == SortRelevance {
+				return nil, fmt.Errorf("sorting by %s requires a search query", SortRelevance)
+			}
+		}
+		return keys, nil
+	}
+	if len(keys) == 0 {
+		return []SortKey{{Field: SortRelevance, Desc: true}}, nil
+	}
+	return keys, nil
+}
+
+func (f *TechnologyFilter) SortKeys() ([]SortKey, error) {
+	return sortKeys(f.Sort, f.SortField, f.SortOrder)
+}
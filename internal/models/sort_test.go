@@ -0,0 +1,114 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []SortKey
+	}{
+		{expr: "", want: nil},
+		{expr: "  ", want: nil},
+		{expr: "title", want: []SortKey{{Field: "title"}}},
+		{expr: "-is_active,title", want: []SortKey{{Field: "is_active", Desc: true}, {Field: "title"}}},
+		{expr: " +version , -created_at ", want: []SortKey{{Field: "version"}, {Field: "created_at", Desc: true}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSort(tt.expr)
+		if err != nil {
+			t.Errorf("ParseSort(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseSort(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+		if tt.want != nil {
+			reparsed, _ := ParseSort(FormatSort(got))
+			if !slices.Equal(reparsed, got) {
+				t.Errorf("ParseSort(FormatSort(%v)) = %v", got, reparsed)
+			}
+		}
+	}
+
+	for _, expr := range []string{"title,", ",title", "-", "title,+", "title,,id"} {
+		if got, err := ParseSort(expr); err == nil {
+			t.Errorf("ParseSort(%q) = %v, want error", expr, got)
+		}
+	}
+}
+
+func TestFormatSort(t *testing.T) {
+	keys := []SortKey{{Field: "is_active", Desc: true}, {Field: "title"}}
+	if got := FormatSort(keys); got != "-is_active,title" {
+		t.Errorf("FormatSort() = %q", got)
+	}
+}
+
+func TestSortKeysDeprecatedFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  TechnologyFilter
+		want    []SortKey
+		wantErr bool
+	}{
+		{name: "none", filter: TechnologyFilter{}, want: nil},
+		{name: "sort_field only", filter: TechnologyFilter{SortField: "name"}, want: []SortKey{{Field: "name"}}},
+		{name: "sort_order desc", filter: TechnologyFilter{SortField: "name", SortOrder: "DESC"}, want: []SortKey{{Field: "name", Desc: true}}},
+		{name: "sort_order asc", filter: TechnologyFilter{SortField: "name", SortOrder: "asc"}, want: []SortKey{{Field: "name"}}},
+		{name: "sort wins", filter: TechnologyFilter{Sort: "-id", SortField: "name", SortOrder: "asc"}, want: []SortKey{{Field: "id", Desc: true}}},
+		{name: "sort_order without sort_field", filter: TechnologyFilter{SortOrder: "desc"}, want: nil},
+		{name: "bad sort_order", filter: TechnologyFilter{SortField: "name", SortOrder: "down"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.filter.SortKeys()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("SortKeys() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SortKeys() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectFilterSortKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  ProjectFilter
+		want    []SortKey
+		wantErr bool
+	}{
+		{name: "default", filter: ProjectFilter{}, want: nil},
+		{name: "search defaults to relevance", filter: ProjectFilter{Q: "go"}, want: []SortKey{{Field: SortRelevance, Desc: true}}},
+		{name: "search with sort", filter: ProjectFilter{Q: "go", Sort: "title"}, want: []SortKey{{Field: "title"}}},
+		{name: "search with deprecated sort", filter: ProjectFilter{Q: "go", SortField: "title", SortOrder: "desc"}, want: []SortKey{{Field: "title", Desc: true}}},
+		{name: "relevance without search", filter: ProjectFilter{Sort: "title,-relevance"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.filter.SortKeys()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("SortKeys() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SortKeys() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+// TechnologyStats is how much a technology is used across projects.
+type TechnologyStats struct {
+	Technology
+	Projects   int64 `json:"projects"`
+	Active     int64 `json:"active"`
+	Archived   int64 `json:"archived"`
+	Developing int64 `json:"developing"`
+	// UsedWith lists the technologies most often used in the same projects.
+	UsedWith []*TechnologyCooccurrence `json:"used_with"`
+}
+
+// TechnologyCooccurrence is a technology used together with another one in
+// Projects projects.
+type TechnologyCooccurrence struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Projects int64  `json:"projects"`
+}
+
+type TechnologyStatsFilter struct {
+	// Top limits UsedWith, DefaultStatsTop when unset.
+	Top uint64 `form:"top" db:"-"`
+}
+
+const (
+	DefaultStatsTop = 5
+	MaxStatsTop     = 50
+)
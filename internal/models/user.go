@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleViewer Role = "viewer"
+)
+
+type User struct {
+	ID           int64     `json:"id" db:"id"`
+	Email        string    `json:"email" db:"email"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	Role         Role      `json:"role" db:"role"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        int64      `db:"id"`
+	UserID    int64      `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+type Credentials struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Principal is the authenticated caller of a request: either a user logged
+// in with a JWT or an automation client using an API key.
+type Principal struct {
+	UserID   int64
+	Role     Role
+	APIKeyID int64
+	Scopes   []Scope
+}
+
+// Can reports whether the caller has scope. Admins have every scope, viewers
+// only read scopes, API keys the scopes they were issued with.
+func (p *Principal) Can(scope Scope) bool {
+	if p.APIKeyID != 0 {
+		return slices.Contains(p.Scopes, scope)
+	}
+	switch p.Role {
+	case RoleAdmin:
+		return true
+	case RoleViewer:
+		return scope.IsRead()
+	}
+	return false
+}
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the caller stored by the auth middleware, or
+// nil for anonymous requests.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/pkg/db/postgres"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+type APIKeyRepository struct {
+	*postgres.DB
+}
+
+func NewAPIKeyRepository(db *postgres.DB) *APIKeyRepository {
+	return &APIKeyRepository{db}
+}
+
+const apiKeyColumns = "id, name, prefix, scopes, quota, quota_used, quota_window_start, expires_at, last_used_at, created_by, created_at, revoked_at"
+
+func scanAPIKey(row sq.RowScanner) (*models.APIKey, error) {
+	var key models.APIKey
+	var scopes pq.StringArray
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &scopes, &key.Quota, &key.QuotaUsed, &key.QuotaWindowStart,
+		&key.ExpiresAt, &key.LastUsedAt, &key.CreatedBy, &key.CreatedAt, &key.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	key.Scopes = make([]models.Scope, len(scopes))
+	for i, scope := range scopes {
+		key.Scopes[i] = models.Scope(scope)
+	}
+	return &key, nil
+}
+
+func scopesArray(scopes []models.Scope) pq.StringArray {
+	result := make(pq.StringArray, len(scopes))
+	for i, scope := range scopes {
+		result[i] = string(scope)
+	}
+	return result
+}
+
+func (repo *APIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	row := sq.Insert("api_keys").
+		Columns("name", "prefix", "key_hash", "scopes", "quota", "expires_at", "created_by").
+		Values(key.Name, key.Prefix, key.KeyHash, scopesArray(key.Scopes), key.Quota, key.ExpiresAt, key.CreatedBy).
+		Suffix("RETURNING " + apiKeyColumns).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx)
+	result, err := scanAPIKey(row)
+	if err != nil {
+		return nil, dbError("repository.CreateAPIKey", err)
+	}
+	return result, nil
+}
+
+func (repo *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	result := []*models.APIKey{}
+	rows, err := sq.Select(apiKeyColumns).
+		From("api_keys").
+		OrderBy("id ASC").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, dbError("repository.ListAPIKeys", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, dbError("repository.ListAPIKeys", err)
+		}
+		result = append(result, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("repository.ListAPIKeys", err)
+	}
+	return result, nil
+}
+
+func (repo *APIKeyRepository) RevokeAPIKey(ctx context.Context, id int64) error {
+	res, err := sq.Update("api_keys").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{"id": id, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.RevokeAPIKey", err)
+	}
+	return expectAffected(res, apperrors.NotFound("api_key_not_found", "Active API key with id %d not found", id))
+}
+
+// UseAPIKey records a request made with the key: it updates last_used_at and
+// counts the request against the quota, starting a new quota window when the
+// current one is older than window. Revoked and expired keys are not found.
+func (repo *APIKeyRepository) UseAPIKey(ctx context.Context, keyHash string, window time.Duration) (*models.APIKey, error) {
+	windowExpired := sq.Expr("quota_window_start <= now() - make_interval(secs => ?)", window.Seconds())
+	row := sq.Update("api_keys").
+		Set("last_used_at", sq.Expr("now()")).
+		Set("quota_window_start", sq.Case().When(windowExpired, "now()").Else("quota_window_start")).
+		Set("quota_used", sq.Case().When(windowExpired, "1").Else("quota_used + 1")).
+		Where(sq.Eq{"key_hash": keyHash, "revoked_at": nil}).
+		Where("(expires_at IS NULL OR expires_at > now())").
+		Suffix("RETURNING " + apiKeyColumns).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx)
+	result, err := scanAPIKey(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("api_key_not_found", "API key not found")
+		}
+		return nil, dbError("repository.UseAPIKey", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+const assetColumns = "id, project_id, filename, content_type, size, caption, position, created_at, storage_key"
+
+func scanAsset(row sq.RowScanner) (*models.ProjectAsset, error) {
+	var asset models.ProjectAsset
+	err := row.Scan(&asset.ID, &asset.ProjectID, &asset.Filename, &asset.ContentType, &asset.Size, &asset.Caption,
+		&asset.Position, &asset.CreatedAt, &asset.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// assetJSON reads the storage key, hidden from API responses, out of the
+// assets aggregated by projectColumns.
+type assetJSON struct {
+	models.ProjectAsset
+	StorageKey string `json:"storage_key"`
+}
+
+func unmarshalAssets(data []byte) ([]*models.ProjectAsset, error) {
+	var rows []assetJSON
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+	assets := make([]*models.ProjectAsset, len(rows))
+	for i := range rows {
+		rows[i].ProjectAsset.StorageKey = rows[i].StorageKey
+		assets[i] = &rows[i].ProjectAsset
+	}
+	return assets, nil
+}
+
+func (repo *PortfolioRepository) CreateAsset(ctx context.Context, asset *models.ProjectAsset) (*models.ProjectAsset, error) {
+	row := sq.Insert("project_assets").
+		Columns("project_id", "storage_key", "filename", "content_type", "size", "caption", "position").
+		Values(asset.ProjectID, asset.StorageKey, asset.Filename, asset.ContentType, asset.Size, asset.Caption, asset.Position).
+		Suffix("RETURNING " + assetColumns).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx)
+	result, err := scanAsset(row)
+	if err != nil {
+		return nil, dbError("repository.CreateAsset", err)
+	}
+	if err := repo.touchProject(ctx, asset.ProjectID); err != nil {
+		return nil, dbError("repository.CreateAsset", err)
+	}
+	return result, nil
+}
+
+// ListProjectAssets returns the assets of a project in order. With
+// forUpdate the rows stay locked until the transaction ends, so concurrent
+// reorders of the same project are serialized.
+func (repo *PortfolioRepository) ListProjectAssets(ctx context.Context, projectID int64, forUpdate bool) ([]*models.ProjectAsset, error) {
+	result := []*models.ProjectAsset{}
+	query := sq.Select(assetColumns).
+		From("project_assets").
+		Where(sq.Eq{"project_id": projectID}).
+		OrderBy("position ASC", "id ASC")
+	if forUpdate {
+		query = query.Suffix("FOR UPDATE")
+	}
+	rows, err := query.PlaceholderFormat(sq.Dollar).RunWith(repo.Querier(ctx)).QueryContext(ctx)
+	if err != nil {
+		return nil, dbError("repository.ListProjectAssets", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, dbError("repository.ListProjectAssets", err)
+		}
+		result = append(result, asset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("repository.ListProjectAssets", err)
+	}
+	return result, nil
+}
+
+func (repo *PortfolioRepository) GetAsset(ctx context.Context, projectID, id int64) (*models.ProjectAsset, error) {
+	row := sq.Select(assetColumns).
+		From("project_assets").
+		Where(sq.Eq{"id": id, "project_id": projectID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx)
+	result, err := scanAsset(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("asset_not_found", "Asset with id %d not found in project %d", id, projectID)
+		}
+		return nil, dbError("repository.GetAsset", err)
+	}
+	return result, nil
+}
+
+func (repo *PortfolioRepository) UpdateAssetCaption(ctx context.Context, projectID, id int64, caption string) error {
+	res, err := sq.Update("project_assets").
+		Set("caption", caption).
+		Where(sq.Eq{"id": id, "project_id": projectID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.UpdateAssetCaption", err)
+	}
+	if err := expectAffected(res, apperrors.NotFound("asset_not_found", "Asset with id %d not found", id)); err != nil {
+		return err
+	}
+	if err := repo.touchProject(ctx, projectID); err != nil {
+		return dbError("repository.UpdateAssetCaption", err)
+	}
+	return nil
+}
+
+// SetAssetPositions numbers the assets of a project in the order of ids,
+// starting from 0.
+func (repo *PortfolioRepository) SetAssetPositions(ctx context.Context, projectID int64, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	res, err := sq.Update("project_assets a").
+		Set("position", sq.Expr("o.ord - 1")).
+		Suffix("FROM unnest(?::integer[]) WITH ORDINALITY AS o(id, ord) WHERE a.id = o.id AND a.project_id = ? AND a.position <> o.ord - 1",
+			pq.Array(ids), projectID).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.SetAssetPositions", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return dbError("repository.SetAssetPositions", err)
+	}
+	if affected == 0 {
+		return nil
+	}
+	if err := repo.touchProject(ctx, projectID); err != nil {
+		return dbError("repository.SetAssetPositions", err)
+	}
+	return nil
+}
+
+func (repo *PortfolioRepository) DeleteAsset(ctx context.Context, projectID, id int64) error {
+	res, err := sq.Delete("project_assets").
+		Where(sq.Eq{"id": id, "project_id": projectID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.DeleteAsset", err)
+	}
+	if err := expectAffected(res, apperrors.NotFound("asset_not_found", "Asset with id %d not found", id)); err != nil {
+		return err
+	}
+	if err := repo.touchProject(ctx, projectID); err != nil {
+		return dbError("repository.DeleteAsset", err)
+	}
+	return nil
+}
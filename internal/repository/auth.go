@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/pkg/db/postgres"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type AuthRepository struct {
+	*postgres.DB
+}
+
+func NewAuthRepository(db *postgres.DB) *AuthRepository {
+	return &AuthRepository{db}
+}
+
+func (repo *AuthRepository) CreateUser(ctx context.Context, user *models.User) (int64, error) {
+	var resultID int64
+	err := sq.Insert("users").
+		Columns("email", "password_hash", "role").
+		Values(user.Email, user.PasswordHash, user.Role).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx).Scan(&resultID)
+	if err != nil {
+		return 0, dbError("repository.CreateUser", err)
+	}
+	return resultID, nil
+}
+
+func (repo *AuthRepository) getUser(ctx context.Context, where sq.Eq) (*models.User, error) {
+	var result models.User
+	err := sq.Select("id, email, password_hash, role, created_at").
+		From("users").
+		Where(where).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx).Scan(&result.ID, &result.Email, &result.PasswordHash, &result.Role, &result.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("user_not_found", "User not found")
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *AuthRepository) GetUser(ctx context.Context, id int64) (*models.User, error) {
+	user, err := repo.getUser(ctx, sq.Eq{"id": id})
+	if err != nil {
+		return nil, dbError("repository.GetUser", err)
+	}
+	return user, nil
+}
+
+func (repo *AuthRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := repo.getUser(ctx, sq.Eq{"email": email})
+	if err != nil {
+		return nil, dbError("repository.GetUserByEmail", err)
+	}
+	return user, nil
+}
+
+func (repo *AuthRepository) UpdateUserPassword(ctx context.Context, id int64, passwordHash string, role models.Role) error {
+	res, err := sq.Update("users").
+		Set("password_hash", passwordHash).
+		Set("role", role).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.UpdateUserPassword", err)
+	}
+	return expectAffected(res, apperrors.NotFound("user_not_found", "User not found"))
+}
+
+func (repo *AuthRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	_, err := sq.Insert("refresh_tokens").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(token.UserID, token.TokenHash, token.ExpiresAt).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.CreateRefreshToken", err)
+	}
+	return nil
+}
+
+// GetRefreshTokenForUpdate locks the token row, so concurrent refreshes with
+// the same token are serialized. Call it inside a transaction.
+func (repo *AuthRepository) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var result models.RefreshToken
+	err := sq.Select("id, user_id, token_hash, expires_at, revoked_at").
+		From("refresh_tokens").
+		Where(sq.Eq{"token_hash": tokenHash}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx).Scan(&result.ID, &result.UserID, &result.TokenHash, &result.ExpiresAt, &result.RevokedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("refresh_token_not_found", "Refresh token not found")
+		}
+		return nil, dbError("repository.GetRefreshTokenForUpdate", err)
+	}
+	return &result, nil
+}
+
+func (repo *AuthRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
+	_, err := sq.Update("refresh_tokens").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{"token_hash": tokenHash, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.RevokeRefreshToken", err)
+	}
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every active refresh token of the user.
+func (repo *AuthRepository) RevokeUserRefreshTokens(ctx context.Context, userID int64) error {
+	_, err := sq.Update("refresh_tokens").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{"user_id": userID, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.RevokeUserRefreshTokens", err)
+	}
+	return nil
+}
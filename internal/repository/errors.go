@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"gowebsite/internal/apperrors"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pqUniqueViolation           = "23505"
+	pqForeignKeyViolation       = "23503"
+	pqNotNullViolation          = "23502"
+	pqCheckViolation            = "23514"
+	pqInvalidTextRepresentation = "22P02"
+	pqStringDataRightTruncation = "22001"
+	pqQueryCanceled             = "57014"
+)
+
+// dbError annotates err with the repository operation and maps Postgres
+// errors to domain errors. Errors that already are domain errors are only
+// annotated.
+func dbError(op string, err error) error {
+	if _, ok := apperrors.As(err); ok {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	cause := fmt.Errorf("%s: %w", op, err)
+	switch pqErr.Code {
+	case pqUniqueViolation:
+		return apperrors.Conflict("already_exists", "Resource already exists").Wrap(cause)
+	case pqForeignKeyViolation:
+		return apperrors.ForeignKey("foreign_key_violation", "Referenced resource does not exist").Wrap(cause)
+	case pqNotNullViolation:
+		return apperrors.Validation("required_field_missing", "Required field %s is missing", pqErr.Column).Wrap(cause)
+	case pqCheckViolation:
+		return apperrors.Validation("constraint_violation", "Value violates constraint %s", pqErr.Constraint).Wrap(cause)
+	case pqInvalidTextRepresentation, pqStringDataRightTruncation:
+		return apperrors.Validation("invalid_value", "Invalid value").Wrap(cause)
+	case pqQueryCanceled:
+		return apperrors.Timeout("query_canceled", "Query was canceled").Wrap(cause)
+	}
+	return cause
+}
+
+// expectAffected returns notFound when the statement touched no rows.
+func expectAffected(res sql.Result, notFound *apperrors.Error) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
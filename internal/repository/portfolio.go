@@ -3,13 +3,14 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"gowebsite/internal/apperrors"
 	"gowebsite/internal/models"
 	"gowebsite/pkg/db/postgres"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/lib/pq"
-	"github.com/volatiletech/null/v9"
 )
 
 type PortfolioRepository struct {
@@ -27,262 +28,702 @@ func (repo *PortfolioRepository) CreateTechnology(ctx context.Context, technolog
 		Values(technology.Name, technology.Svg).
 		Suffix("RETURNING id").
 		PlaceholderFormat(sq.Dollar).
-		RunWith(repo.DB.DB).
-		QueryRow().Scan(&resultID)
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx).Scan(&resultID)
 	if err != nil {
-		return 0, fmt.Errorf("repository.CreateTechnology: %v", err)
+		return 0, dbError("repository.CreateTechnology", err)
 	}
 	return resultID, nil
 }
 
+const technologyColumns = "t.id, t.name, t.svg, t.created_at, t.updated_at, t.deleted_at"
+
+func scanTechnology(row sq.RowScanner, technology *models.Technology, extra ...any) error {
+	dest := []any{&technology.ID, &technology.Name, &technology.Svg, &technology.CreatedAt, &technology.UpdatedAt, &technology.DeletedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
 func (repo *PortfolioRepository) GetTechnology(ctx context.Context, id int64) (*models.Technology, error) {
 	var result models.Technology
-	err := sq.Select("*").
-		From("techs").
-		Where(sq.Eq{"id": id}).
+	row := sq.Select(technologyColumns).
+		From("techs t").
+		Where(sq.Eq{"t.id": id, "t.deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar).
-		RunWith(repo.DB.DB).
-		QueryRow().Scan(&result.ID, &result.Name, &result.Svg)
-	if err != nil {
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx)
+	if err := scanTechnology(row, &result); err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, apperrors.NotFound("technology_not_found", "Technology with id %d not found", id)
 		}
-		return nil, fmt.Errorf("repository.GetTechnology: %v", err)
+		return nil, dbError("repository.GetTechnology", err)
 	}
 	return &result, nil
 }
 
-func (repo *PortfolioRepository) ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, error) {
-	var result []*models.Technology
-
-	query := sq.Select("*").From("techs").PlaceholderFormat(sq.Dollar)
-	if filter.TechnologiesID != nil {
-		query = query.Where(sq.Eq{"id": *filter.TechnologiesID})
+// ExistingTechnologyIDs returns the ids out of ids that belong to a
+// technology which is not in the trash.
+func (repo *PortfolioRepository) ExistingTechnologyIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	result := []int64{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+	rows, err := sq.Select("id").
+		From("techs").
+		Where("id = ANY(?)", pq.Array(ids)).
+		Where(sq.Eq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, dbError("repository.ExistingTechnologyIDs", err)
 	}
+	defer rows.Close()
 
-	if filter.SortField != "" {
-		if filter.SortOrder == "" {
-			filter.SortOrder = "ASC"
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, dbError("repository.ExistingTechnologyIDs", err)
 		}
-		query = query.OrderBy(fmt.Sprintf("%s %s", filter.SortField, filter.SortOrder),
-			"techs.name ASC")
+		result = append(result, id)
 	}
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
+	if err := rows.Err(); err != nil {
+		return nil, dbError("repository.ExistingTechnologyIDs", err)
 	}
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
+	return result, nil
+}
+
+func (repo *PortfolioRepository) ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, *models.PageInfo, error) {
+	result := []*models.Technology{}
+
+	page, err := technologySort.paginate(filter.SortKeys, filter.Cursor, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	rows, err := query.RunWith(repo.DB.DB).Query()
+	query := filterTechnologies(sq.Select(technologyColumns).From("techs t"), filter)
+	rows, err := page.apply(query).PlaceholderFormat(sq.Dollar).RunWith(repo.Querier(ctx)).QueryContext(ctx)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return result, fmt.Errorf("repository.ListTechnologies: %v", err)
+		return nil, nil, dbError("repository.ListTechnologies", err)
 	}
+	defer rows.Close()
+
 	for rows.Next() {
 		var technology models.Technology
-		if err := rows.Scan(&technology.ID, &technology.Name, &technology.Svg); err != nil {
-			return nil, fmt.Errorf("repository.ListTechnologies: %v", err)
+		if err := scanTechnology(rows, &technology); err != nil {
+			return nil, nil, dbError("repository.ListTechnologies", err)
 		}
 		result = append(result, &technology)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, dbError("repository.ListTechnologies", err)
+	}
+
+	total, err := repo.count(ctx, filterTechnologies(sq.Select("COUNT(*)").From("techs t"), filter))
+	if err != nil {
+		return nil, nil, dbError("repository.ListTechnologies", err)
+	}
+	result, info := page.finish(result, total)
+	return result, info, nil
+}
+
+func filterTechnologies(query sq.SelectBuilder, filter *models.TechnologyFilter) sq.SelectBuilder {
+	query = query.Where(inTrash("t.deleted_at", filter.Deleted))
+	if filter.TechnologiesID != nil {
+		query = query.Where(sq.Eq{"t.id": *filter.TechnologiesID})
+	}
+	return query
+}
+
+// TechnologyStats counts the projects using each technology by status and
+// finds the top technologies used together with it, most used first.
+func (repo *PortfolioRepository) TechnologyStats(ctx context.Context, top uint64) ([]*models.TechnologyStats, error) {
+	result := []*models.TechnologyStats{}
+	rows, err := sq.Select(
+		technologyColumns,
+		"COUNT(p.id)",
+		"COUNT(p.id) FILTER (WHERE p.is_active)",
+		"COUNT(p.id) FILTER (WHERE p.is_archived)",
+		"COUNT(p.id) FILTER (WHERE p.is_developing)",
+	).
+		Column(`COALESCE((
+			SELECT json_agg(json_build_object('id', c.id, 'name', c.name, 'projects', c.projects) ORDER BY c.projects DESC, c.name, c.id)
+			FROM (
+				SELECT o.id, o.name, COUNT(*) AS projects
+				FROM project_tech a
+				JOIN project_tech b ON b.project_id = a.project_id AND b.tech_id <> a.tech_id
+				JOIN techs o ON o.id = b.tech_id AND o.deleted_at IS NULL
+				JOIN projects cp ON cp.id = a.project_id AND cp.deleted_at IS NULL
+				WHERE a.tech_id = t.id
+				GROUP BY o.id, o.name
+				ORDER BY projects DESC, o.name, o.id
+				LIMIT ?
+			) c
+		), '[]')`, top).
+		From("techs t").
+		LeftJoin("project_tech pt ON pt.tech_id = t.id").
+		LeftJoin("projects p ON p.id = pt.project_id AND p.deleted_at IS NULL").
+		Where(sq.Eq{"t.deleted_at": nil}).
+		GroupBy("t.id").
+		OrderBy("COUNT(p.id) DESC", "t.name ASC", "t.id ASC").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, dbError("repository.TechnologyStats", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stats models.TechnologyStats
+		var usedWith []byte
+		err := scanTechnology(rows, &stats.Technology, &stats.Projects, &stats.Active, &stats.Archived, &stats.Developing, &usedWith)
+		if err != nil {
+			return nil, dbError("repository.TechnologyStats", err)
+		}
+		if err := json.Unmarshal(usedWith, &stats.UsedWith); err != nil {
+			return nil, dbError("repository.TechnologyStats", err)
+		}
+		result = append(result, &stats)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("repository.TechnologyStats", err)
+	}
 	return result, nil
 }
 
+// inTrash matches the rows in the trash, or with deleted false the live
+// ones, by their deleted_at column.
+func inTrash(column string, deleted bool) sq.Sqlizer {
+	if deleted {
+		return sq.NotEq{column: nil}
+	}
+	return sq.Eq{column: nil}
+}
+
+// count runs a COUNT(*) query built from the same filters as a list.
+func (repo *PortfolioRepository) count(ctx context.Context, query sq.SelectBuilder) (int64, error) {
+	var total int64
+	err := query.PlaceholderFormat(sq.Dollar).RunWith(repo.Querier(ctx)).QueryRowContext(ctx).Scan(&total)
+	return total, err
+}
+
+// DeleteTechnology moves a technology to the trash. Its project_tech rows
+// are kept, hidden from the projects, so RestoreTechnology can bring them
+// back.
 func (repo *PortfolioRepository) DeleteTechnology(ctx context.Context, id int64) error {
-	_, err := sq.Delete("techs").
+	return repo.WithTx(ctx, func(ctx context.Context) error {
+		res, err := sq.Update("techs").
+			Set("deleted_at", sq.Expr("now()")).
+			Set("updated_at", sq.Expr("now()")).
+			Where(sq.Eq{"id": id, "deleted_at": nil}).
+			PlaceholderFormat(sq.Dollar).
+			RunWith(repo.Querier(ctx)).ExecContext(ctx)
+		if err != nil {
+			return dbError("repository.DeleteTechnology", err)
+		}
+		if err := expectAffected(res, apperrors.NotFound("technology_not_found", "Technology with id %d not found", id)); err != nil {
+			return err
+		}
+		if err := repo.touchProjectsUsing(ctx, id); err != nil {
+			return dbError("repository.DeleteTechnology", err)
+		}
+		return nil
+	})
+}
+
+// RestoreTechnology takes a technology out of the trash together with its
+// links to projects.
+func (repo *PortfolioRepository) RestoreTechnology(ctx context.Context, id int64) error {
+	return repo.WithTx(ctx, func(ctx context.Context) error {
+		res, err := sq.Update("techs").
+			Set("deleted_at", nil).
+			Set("updated_at", sq.Expr("now()")).
+			Where(sq.Eq{"id": id}).
+			Where(sq.NotEq{"deleted_at": nil}).
+			PlaceholderFormat(sq.Dollar).
+			RunWith(repo.Querier(ctx)).ExecContext(ctx)
+		if err != nil {
+			return dbError("repository.RestoreTechnology", err)
+		}
+		if err := expectAffected(res, apperrors.NotFound("technology_not_found", "Technology with id %d not found in the trash", id)); err != nil {
+			return err
+		}
+		if err := repo.touchProjectsUsing(ctx, id); err != nil {
+			return dbError("repository.RestoreTechnology", err)
+		}
+		return nil
+	})
+}
+
+// touchProject marks a project as updated when something it embeds in its
+// responses, such as its assets, changes.
+func (repo *PortfolioRepository) touchProject(ctx context.Context, id int64) error {
+	_, err := sq.Update("projects").
+		Set("updated_at", sq.Expr("now()")).
 		Where(sq.Eq{"id": id}).
 		PlaceholderFormat(sq.Dollar).
-		RunWith(repo.DB.DB).Query()
-	if err != nil {
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	return err
+}
 
-		return fmt.Errorf("repository.DeleteTechnology: %v", err)
-	}
-	return nil
+// touchProjectsUsing marks the projects using a technology as updated.
+func (repo *PortfolioRepository) touchProjectsUsing(ctx context.Context, technologyID int64) error {
+	_, err := sq.Update("projects").
+		Set("updated_at", sq.Expr("now()")).
+		Where("id IN (SELECT project_id FROM project_tech WHERE tech_id = ?)", technologyID).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	return err
 }
 
 func (repo *PortfolioRepository) CreateProject(ctx context.Context, project *models.Project) (int64, error) {
 	var resultID int64
-	Links := pq.StringArray(project.Links)
-	err := sq.Insert("projects").
-		Columns("title", "version", "description", "is_active", "is_archived", "is_developing", "links").
-		Values(project.Title, project.Version, project.Description, project.IsActive, project.IsArchived, project.IsDeveloping, Links).
-		Suffix("RETURNING id").
+	err := repo.WithTx(ctx, func(ctx context.Context) error {
+		slug := project.Slug
+		if slug == "" {
+			var err error
+			if slug, err = repo.freeSlug(ctx, models.Slugify(project.Title)); err != nil {
+				return err
+			}
+		}
+		Links := pq.StringArray(project.Links)
+		err := sq.Insert("projects").
+			Columns("title", "slug", "version", "description", "is_active", "is_archived", "is_developing", "links").
+			Values(project.Title, slug, project.Version, project.Description, project.IsActive, project.IsArchived, project.IsDeveloping, Links).
+			Suffix("RETURNING id").
+			PlaceholderFormat(sq.Dollar).
+			RunWith(repo.Querier(ctx)).
+			QueryRowContext(ctx).Scan(&resultID)
+		if err != nil {
+			return err
+		}
+		if err := repo.addRelease(ctx, resultID, project.Version); err != nil {
+			return err
+		}
+		return repo.setProjectTechnologies(ctx, resultID, project.TechnologyIDs)
+	})
+	if err != nil {
+		return 0, dbError("repository.CreateProject", err)
+	}
+	return resultID, nil
+}
+
+// freeSlug returns base, or base with the lowest number from 2 appended
+// that no project, including those in the trash, uses yet.
+func (repo *PortfolioRepository) freeSlug(ctx context.Context, base string) (string, error) {
+	rows, err := sq.Select("slug").
+		From("projects").
+		Where(sq.Or{sq.Eq{"slug": base}, sq.Like{"slug": base + "-%"}}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	taken := map[string]bool{}
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return "", err
+		}
+		taken[slug] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	slug := base
+	for n := 2; taken[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return slug, nil
+}
+
+// setProjectTechnologies replaces the technologies linked to the project.
+// Links to technologies in the trash are kept for when they are restored.
+// Callers are expected to run it inside a transaction.
+func (repo *PortfolioRepository) setProjectTechnologies(ctx context.Context, projectID int64, technologyIDs []int64) error {
+	_, err := sq.Delete("project_tech").
+		Where(sq.Eq{"project_id": projectID}).
+		Where("tech_id IN (SELECT id FROM techs WHERE deleted_at IS NULL)").
 		PlaceholderFormat(sq.Dollar).
-		RunWith(repo.DB.DB).
-		QueryRow().Scan(&resultID)
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("repository.CreateProject: %v", err)
+		return err
+	}
+	if len(technologyIDs) == 0 {
+		return nil
 	}
 
 	query := sq.Insert("project_tech").Columns("project_id", "tech_id").PlaceholderFormat(sq.Dollar)
+	for _, technologyID := range technologyIDs {
+		query = query.Values(projectID, technologyID)
+	}
+	_, err = query.RunWith(repo.Querier(ctx)).ExecContext(ctx)
+	return err
+}
 
-	for _, technologyID := range project.TechnologyIDs {
-		query = query.Values(resultID, technologyID)
+// projectColumns selects one row per project with its technologies and
+// assets aggregated into JSON arrays, so LIMIT/OFFSET page over projects and
+// projects without technologies are kept.
+const projectColumns = `p.id, p.title, p.slug, p.version, p.description, p.is_active, p.is_archived, p.is_developing, p.links,
+	p.version_key, p.created_at, p.updated_at, p.deleted_at,
+	COALESCE((
+		SELECT json_agg(json_build_object('id', t.id, 'name', t.name, 'svg', t.svg,
+			'created_at', t.created_at, 'updated_at', t.updated_at) ORDER BY t.name, t.id)
+		FROM project_tech pt
+		JOIN techs t ON t.id = pt.tech_id
+		WHERE pt.project_id = p.id AND t.deleted_at IS NULL
+	), '[]') AS technologies,
+	COALESCE((
+		SELECT json_agg(json_build_object('id', a.id, 'project_id', a.project_id, 'filename', a.filename,
+			'content_type', a.content_type, 'size', a.size, 'caption', a.caption, 'position', a.position,
+			'created_at', a.created_at, 'storage_key', a.storage_key) ORDER BY a.position, a.id)
+		FROM project_assets a
+		WHERE a.project_id = p.id
+	), '[]') AS assets`
+
+// searchColumns are selected after projectColumns from searchProjects.
+const searchColumns = "p.rank, p.title_highlight, p.description_highlight"
+
+func scanProject(row sq.RowScanner) (*models.Project, error) {
+	return scanProjectWith(row)
+}
+
+func scanSearchedProject(row sq.RowScanner) (*models.Project, error) {
+	search := &models.SearchResult{}
+	project, err := scanProjectWith(row, &search.Rank, &search.TitleHighlight, &search.DescriptionHighlight)
+	if err != nil {
+		return nil, err
 	}
+	project.Search = search
+	return project, nil
+}
 
-	_, err = query.RunWith(repo.DB.DB).Exec()
+// scanProjectWith scans projectColumns followed by extra columns.
+func scanProjectWith(row sq.RowScanner, extra ...any) (*models.Project, error) {
+	var project models.Project
+	var links pq.StringArray
+	var technologies, assets []byte
+	dest := []any{&project.ID, &project.Title, &project.Slug, &project.Version, &project.Description, &project.IsActive, &project.IsArchived, &project.IsDeveloping, &links,
+		&project.VersionKey, &project.CreatedAt, &project.UpdatedAt, &project.DeletedAt, &technologies, &assets}
+	err := row.Scan(append(dest, extra...)...)
 	if err != nil {
-		return 0, fmt.Errorf("repository.CreateProject: %v", err)
+		return nil, err
 	}
-	return resultID, nil
+	project.Links = links
+	if err := json.Unmarshal(technologies, &project.Technologies); err != nil {
+		return nil, err
+	}
+	project.TechnologyIDs = make([]int64, len(project.Technologies))
+	for i, technology := range project.Technologies {
+		project.TechnologyIDs[i] = technology.ID
+	}
+	if project.Assets, err = unmarshalAssets(assets); err != nil {
+		return nil, err
+	}
+	return &project, nil
 }
 
 func (repo *PortfolioRepository) GetProject(ctx context.Context, id int64) (*models.Project, error) {
+	return repo.getProject(ctx, id, false)
+}
 
-	query := sq.Select("p.id, p.title, p.version, p.description, p.is_active, p.is_archived, p.is_developing, p.links, t.id AS tech_id, t.name AS tech_name, t.svg AS tech_svg").
+// GetProjectForUpdate reads the project like GetProject and locks its row
+// until the transaction ends, so a read-modify-write of the project can't
+// interleave with another write.
+func (repo *PortfolioRepository) GetProjectForUpdate(ctx context.Context, id int64) (*models.Project, error) {
+	return repo.getProject(ctx, id, true)
+}
+
+func (repo *PortfolioRepository) getProject(ctx context.Context, id int64, forUpdate bool) (*models.Project, error) {
+	query := sq.Select(projectColumns).
 		From("projects p").
-		Join("project_tech pt ON p.id = pt.project_id").
-		Join("techs t ON pt.tech_id = t.id").
-		Where(sq.Eq{"p.id": id}).
-		PlaceholderFormat(sq.Dollar)
+		Where(sq.Eq{"p.id": id, "p.deleted_at": nil})
+	if forUpdate {
+		query = query.Suffix("FOR UPDATE OF p")
+	}
+	row := query.PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx)
+
+	result, err := scanProject(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("project_not_found", "Project with id %d not found", id)
+		}
+		return nil, dbError("repository.GetProject", err)
+	}
+	return result, nil
+}
+
+func (repo *PortfolioRepository) GetProjectBySlug(ctx context.Context, slug string) (*models.Project, error) {
+	row := sq.Select(projectColumns).
+		From("projects p").
+		Where(sq.Eq{"p.slug": slug, "p.deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx)
 
-	rows, err := query.RunWith(repo.DB.DB).Query()
+	result, err := scanProject(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, apperrors.NotFound("project_not_found", "Project %q not found", slug)
 		}
-		return nil, fmt.Errorf("repository.GetProject: %v", err)
+		return nil, dbError("repository.GetProjectBySlug", err)
 	}
-	var result models.Project
-	result.Technologies = []*models.Technology{}
+	return result, nil
+}
+
+func (repo *PortfolioRepository) ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, *models.PageInfo, error) {
+	result := []*models.Project{}
+
+	if filter.TechMatch != "" && !filter.TechMatch.IsValid() {
+		return nil, nil, apperrors.BadRequest("invalid_tech_match", "tech_match must be all, any or none, got %q", filter.TechMatch)
+	}
+	page, err := projectSort.paginate(filter.SortKeys, filter.Cursor, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query, scan := sq.Select(projectColumns).From("projects p"), scanProject
+	if filter.Q != "" {
+		query, scan = sq.Select(projectColumns, searchColumns).FromSelect(searchProjects(filter.Q), "p"), scanSearchedProject
+	}
+	query = filterProjects(query, filter)
+	rows, err := page.apply(query).PlaceholderFormat(sq.Dollar).RunWith(repo.Querier(ctx)).QueryContext(ctx)
+	if err != nil {
+		return nil, nil, dbError("repository.ListProjects", err)
+	}
+	defer rows.Close()
+
 	for rows.Next() {
-		var technology models.Technology
-		var links pq.StringArray
-		err := rows.Scan(&result.ID, &result.Title, &result.Version, &result.Description, &result.IsActive, &result.IsArchived, &result.IsDeveloping, &links, &technology.ID, &technology.Name, &technology.Svg)
+		project, err := scan(rows)
 		if err != nil {
-			return nil, fmt.Errorf("repository.GetProject: %v", err)
+			return nil, nil, dbError("repository.ListProjects", err)
 		}
-		result.Links = links
-		result.Technologies = append(result.Technologies, &technology)
+		result = append(result, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, dbError("repository.ListProjects", err)
 	}
 
-	return &result, nil
+	countQuery := sq.Select("COUNT(*)").From("projects p")
+	if filter.Q != "" {
+		countQuery = countQuery.Where("p.search_vector @@ websearch_to_tsquery('public.portfolio', ?)", filter.Q)
+	}
+	total, err := repo.count(ctx, filterProjects(countQuery, filter))
+	if err != nil {
+		return nil, nil, dbError("repository.ListProjects", err)
+	}
+	result, info := page.finish(result, total)
+	return result, info, nil
 }
 
-func (repo *PortfolioRepository) ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, error) {
-	var result []*models.Project
-	query := sq.Select("p.id, p.title, p.version, p.description, p.is_active, p.is_archived, p.is_developing, p.links, t.id AS tech_id, t.name AS tech_name, t.svg AS tech_svg").
+// searchProjects selects the projects matching q together with their rank
+// and highlighted title and description. It is used as the "p" relation so
+// that the rank can be sorted and paged by like a column.
+func searchProjects(q string) sq.SelectBuilder {
+	return sq.Select(
+		"p.*",
+		"ts_rank_cd(p.search_vector, query)::float8 AS rank",
+		"ts_headline('public.portfolio', p.title, query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight",
+		"ts_headline('public.portfolio', p.description, query, 'MaxFragments=2, MaxWords=30, MinWords=10, StartSel=<mark>, StopSel=</mark>') AS description_highlight",
+	).
 		From("projects p").
-		Join("project_tech pt ON p.id = pt.project_id").
-		Join("techs t ON pt.tech_id = t.id").
-		PlaceholderFormat(sq.Dollar)
+		JoinClause("CROSS JOIN websearch_to_tsquery('public.portfolio', ?) AS query", q).
+		Where("p.search_vector @@ query")
+}
 
+// usesTechnologies matches projects linked to any of the technologies.
+// Links to technologies in the trash are kept for restoring them but don't
+// count, like everywhere else they are hidden.
+const usesTechnologies = `EXISTS (
+	SELECT 1 FROM project_tech pt
+	JOIN techs t ON t.id = pt.tech_id AND t.deleted_at IS NULL
+	WHERE pt.project_id = p.id AND pt.tech_id = ANY(?)
+)`
+
+// usesAllTechnologies matches projects for which no technology out of the
+// given ones is missing. A technology in the trash is missing from every
+// project.
+const usesAllTechnologies = `NOT EXISTS (
+	SELECT 1 FROM unnest(?::integer[]) AS wanted(tech_id)
+	WHERE NOT EXISTS (
+		SELECT 1 FROM project_tech pt
+		JOIN techs t ON t.id = pt.tech_id AND t.deleted_at IS NULL
+		WHERE pt.project_id = p.id AND pt.tech_id = wanted.tech_id
+	)
+)`
+
+func filterProjects(query sq.SelectBuilder, filter *models.ProjectFilter) sq.SelectBuilder {
+	query = query.Where(inTrash("p.deleted_at", filter.Deleted))
 	if filter.TechnologiesID != nil {
-		query = query.Where(sq.Eq{"t.id": filter.TechnologiesID})
+		ids := *filter.TechnologiesID
+		switch filter.TechMatch {
+		case models.TechMatchAll:
+			query = query.Where(usesAllTechnologies, pq.Array(ids))
+		case models.TechMatchNone:
+			query = query.Where("NOT "+usesTechnologies, pq.Array(ids))
+		default:
+			query = query.Where(usesTechnologies, pq.Array(ids))
+		}
+	}
+	if filter.ExcludeTechnologiesID != nil {
+		query = query.Where("NOT "+usesTechnologies, pq.Array(*filter.ExcludeTechnologiesID))
 	}
 	if filter.IsActive != nil {
-		query = query.Where(sq.Eq{"is_active": *filter.IsActive})
+		query = query.Where(sq.Eq{"p.is_active": *filter.IsActive})
 	}
 	if filter.IsArchived != nil {
-		query = query.Where(sq.Eq{"is_archived": *filter.IsArchived})
+		query = query.Where(sq.Eq{"p.is_archived": *filter.IsArchived})
 	}
 	if filter.IsDeveloping != nil {
-		query = query.Where(sq.Eq{"is_developing": *filter.IsDeveloping})
+		query = query.Where(sq.Eq{"p.is_developing": *filter.IsDeveloping})
 	}
-	query = query.PlaceholderFormat(sq.Dollar)
+	return query
+}
 
-	if filter.SortField != "" {
-		if filter.SortOrder == "" {
-			filter.SortOrder = "ASC"
-		}
-		query = query.OrderBy(
-			fmt.Sprintf("%s %s", filter.SortField, filter.SortOrder),
-			"projects.title ASC",
-		)
+// DeleteProject moves a project to the trash. Its technology links and
+// assets are kept so RestoreProject can bring it back as it was.
+func (repo *PortfolioRepository) DeleteProject(ctx context.Context, id int64) error {
+	res, err := sq.Update("projects").
+		Set("deleted_at", sq.Expr("now()")).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.DeleteProject", err)
 	}
+	return expectAffected(res, apperrors.NotFound("project_not_found", "Project with id %d not found", id))
+}
 
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
-	}
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
-	}
-	rows, err := query.RunWith(repo.DB.DB).Query()
+// RestoreProject takes a project out of the trash.
+func (repo *PortfolioRepository) RestoreProject(ctx context.Context, id int64) error {
+	res, err := sq.Update("projects").
+		Set("deleted_at", nil).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return result, nil
-		}
-		return nil, fmt.Errorf("repository.ListProjects: %v", err)
+		return dbError("repository.RestoreProject", err)
 	}
+	return expectAffected(res, apperrors.NotFound("project_not_found", "Project with id %d not found in the trash", id))
+}
 
-	var currentProject *models.Project
-	for rows.Next() {
-		var projectID, technologyID int64
-		var projectTitle, projectVersion, projectDescription, techName string
-		var projectIsActive, projectIsArchived, projectIsDeveloping null.Bool
-		var projectLinks pq.StringArray
-		var techSvg null.String
-
-		err := rows.Scan(&projectID, &projectTitle, &projectVersion, &projectDescription, &projectIsActive, &projectIsArchived, &projectIsDeveloping, &projectLinks, &technologyID, &techName, &techSvg)
+// UpdateTechnology overwrites every field of a technology, unlike
+// PatchTechnology which skips the empty ones.
+func (repo *PortfolioRepository) UpdateTechnology(ctx context.Context, technology *models.Technology) error {
+	return repo.WithTx(ctx, func(ctx context.Context) error {
+		res, err := sq.Update("techs").
+			Set("name", technology.Name).
+			Set("svg", technology.Svg).
+			Set("updated_at", sq.Expr("now()")).
+			Where(sq.Eq{"id": technology.ID, "deleted_at": nil}).
+			PlaceholderFormat(sq.Dollar).
+			RunWith(repo.Querier(ctx)).
+			ExecContext(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("repository.ListProjects: %v", err)
+			return dbError("repository.UpdateTechnology", err)
 		}
-
-		if currentProject == nil || currentProject.ID != projectID {
-			if currentProject != nil {
-				result = append(result, currentProject)
-			}
-			currentProject = &models.Project{
-				ID:           projectID,
-				Title:        projectTitle,
-				Version:      projectVersion,
-				Description:  projectDescription,
-				IsActive:     projectIsActive,
-				IsArchived:   projectIsArchived,
-				IsDeveloping: projectIsDeveloping,
-				Links:        projectLinks,
-			}
+		if err := expectAffected(res, apperrors.NotFound("technology_not_found", "Technology with id %d not found", technology.ID)); err != nil {
+			return err
 		}
-
-		currentProject.Technologies = append(currentProject.Technologies,
-			&models.Technology{
-				ID:   technologyID,
-				Name: techName,
-				Svg:  techSvg,
-			})
-	}
-	result = append(result, currentProject)
-	return result, nil
+		if err := repo.touchProjectsUsing(ctx, technology.ID); err != nil {
+			return dbError("repository.UpdateTechnology", err)
+		}
+		return nil
+	})
 }
 
-func (repo *PortfolioRepository) DeleteProject(ctx context.Context, id int64) error {
-	_, err := sq.Delete("projects").
-		Where(sq.Eq{"id": id}).
-		PlaceholderFormat(sq.Dollar).
-		RunWith(repo.DB.DB).
-		Query()
-	if err != nil {
-
-		return fmt.Errorf("repository.DeleteProject: %v", err)
-	}
-	return nil
+// UpdateProject overwrites every field of a project and its technologies,
+// unlike PatchProject which skips the empty ones. Like PatchProject, it sets
+// the version by adding a release, so the project keeps a later release as
+// its version. An empty slug keeps the current one.
+func (repo *PortfolioRepository) UpdateProject(ctx context.Context, project *models.Project) error {
+	return repo.WithTx(ctx, func(ctx context.Context) error {
+		query := sq.Update("projects")
+		if project.Slug != "" {
+			query = query.Set("slug", project.Slug)
+		}
+		res, err := query.
+			Set("title", project.Title).
+			Set("description", project.Description).
+			Set("is_active", project.IsActive).
+			Set("is_archived", project.IsArchived).
+			Set("is_developing", project.IsDeveloping).
+			Set("links", pq.StringArray(project.Links)).
+			Set("updated_at", sq.Expr("now()")).
+			Where(sq.Eq{"id": project.ID, "deleted_at": nil}).
+			PlaceholderFormat(sq.Dollar).
+			RunWith(repo.Querier(ctx)).
+			ExecContext(ctx)
+		if err != nil {
+			return dbError("repository.UpdateProject", err)
+		}
+		if err := expectAffected(res, apperrors.NotFound("project_not_found", "Project with id %d not found", project.ID)); err != nil {
+			return err
+		}
+		if err := repo.addRelease(ctx, project.ID, project.Version); err != nil {
+			return dbError("repository.UpdateProject", err)
+		}
+		if err := repo.syncProjectVersion(ctx, project.ID); err != nil {
+			return dbError("repository.UpdateProject", err)
+		}
+		if err := repo.setProjectTechnologies(ctx, project.ID, project.TechnologyIDs); err != nil {
+			return dbError("repository.UpdateProject", err)
+		}
+		return nil
+	})
 }
 
 func (repo *PortfolioRepository) PatchTechnology(ctx context.Context, technology *models.Technology) error {
 
-	query := sq.Update("techs").Where(sq.Eq{"id": technology.ID}).PlaceholderFormat(sq.Dollar)
+	query := sq.Update("techs").Where(sq.Eq{"id": technology.ID, "deleted_at": nil}).PlaceholderFormat(sq.Dollar)
 
+	isNoUpdate := true
 	if technology.Name != "" {
+		isNoUpdate = false
 		query = query.Set("name", technology.Name)
 	}
 
 	if technology.Svg.Valid {
+		isNoUpdate = false
 		query = query.Set("svg", technology.Svg)
 	}
 
-	_, err := query.RunWith(repo.DB.DB).Exec()
-	if err != nil {
-
-		return fmt.Errorf("repository.PatchTechnology: %v", err)
+	if isNoUpdate {
+		_, err := repo.GetTechnology(ctx, technology.ID)
+		return err
 	}
-	return nil
+	query = query.Set("updated_at", sq.Expr("now()"))
+
+	return repo.WithTx(ctx, func(ctx context.Context) error {
+		res, err := query.RunWith(repo.Querier(ctx)).ExecContext(ctx)
+		if err != nil {
+			return dbError("repository.PatchTechnology", err)
+		}
+		if err := expectAffected(res, apperrors.NotFound("technology_not_found", "Technology with id %d not found", technology.ID)); err != nil {
+			return err
+		}
+		if err := repo.touchProjectsUsing(ctx, technology.ID); err != nil {
+			return dbError("repository.PatchTechnology", err)
+		}
+		return nil
+	})
 }
 
 func (repo *PortfolioRepository) PatchProject(ctx context.Context, project *models.Project, projectUpdate *models.Project) error {
-	query := sq.Update("projects").Where(sq.Eq{"id": project.ID}).PlaceholderFormat(sq.Dollar)
+	query := sq.Update("projects").Where(sq.Eq{"id": project.ID, "deleted_at": nil}).PlaceholderFormat(sq.Dollar)
 
 	isNoUpdate := true
 	if projectUpdate.Title != "" {
@@ -290,9 +731,14 @@ func (repo *PortfolioRepository) PatchProject(ctx context.Context, project *mode
 		query = query.Set("title", projectUpdate.Title)
 	}
 
+	// The version is set by syncProjectVersion.
 	if projectUpdate.Version != "" {
 		isNoUpdate = false
-		query = query.Set("version", projectUpdate.Version)
+	}
+
+	if projectUpdate.Slug != "" {
+		isNoUpdate = false
+		query = query.Set("slug", projectUpdate.Slug)
 	}
 
 	if projectUpdate.Description != "" {
@@ -319,27 +765,37 @@ func (repo *PortfolioRepository) PatchProject(ctx context.Context, project *mode
 		Links := pq.StringArray(projectUpdate.Links)
 		query = query.Set("links", Links)
 	}
-	if !isNoUpdate {
-		_, err := query.RunWith(repo.DB.DB).Exec()
 
-		if err != nil {
-			return fmt.Errorf("repository.UpdateProject: %v", err)
-		}
-	}
-	deleteQuery := sq.Delete("project_tech").Where(sq.Eq{"project_id": project.ID}).PlaceholderFormat(sq.Dollar)
-	createQuery := sq.Insert("project_tech").Columns("project_id", "tech_id").PlaceholderFormat(sq.Dollar)
 	if projectUpdate.TechnologyIDs != nil {
-		for _, technologyID := range projectUpdate.TechnologyIDs {
-			createQuery = createQuery.Values(project.ID, technologyID)
+		isNoUpdate = false
+	}
+	query = query.Set("updated_at", sq.Expr("now()"))
+
+	err := repo.WithTx(ctx, func(ctx context.Context) error {
+		if !isNoUpdate {
+			res, err := query.RunWith(repo.Querier(ctx)).ExecContext(ctx)
+			if err != nil {
+				return err
+			}
+			if err := expectAffected(res, apperrors.NotFound("project_not_found", "Project with id %d not found", project.ID)); err != nil {
+				return err
+			}
 		}
-		_, err := deleteQuery.RunWith(repo.DB.DB).Exec()
-		if err != nil {
-			return fmt.Errorf("repository.UpdateProject: %v", err)
+		if projectUpdate.Version != "" {
+			if err := repo.addRelease(ctx, project.ID, projectUpdate.Version); err != nil {
+				return err
+			}
+			if err := repo.syncProjectVersion(ctx, project.ID); err != nil {
+				return err
+			}
 		}
-		_, err = createQuery.RunWith(repo.DB.DB).Exec()
-		if err != nil {
-			return fmt.Errorf("repository.UpdateProject: %v", err)
+		if projectUpdate.TechnologyIDs != nil {
+			return repo.setProjectTechnologies(ctx, project.ID, projectUpdate.TechnologyIDs)
 		}
+		return nil
+	})
+	if err != nil {
+		return dbError("repository.UpdateProject", err)
 	}
 
 	return nil
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+const releaseColumns = "r.id, r.project_id, r.version, r.version_key, r.released_at, r.notes, r.artifacts, r.created_at, r.updated_at"
+
+func scanRelease(row sq.RowScanner) (*models.Release, error) {
+	var release models.Release
+	var artifacts pq.StringArray
+	err := row.Scan(&release.ID, &release.ProjectID, &release.Version, &release.VersionKey, &release.ReleasedAt,
+		&release.Notes, &artifacts, &release.CreatedAt, &release.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	release.Artifacts = artifacts
+	return &release, nil
+}
+
+func (repo *PortfolioRepository) CreateRelease(ctx context.Context, release *models.Release) (*models.Release, error) {
+	var releasedAt any = release.ReleasedAt
+	if release.ReleasedAt.IsZero() {
+		releasedAt = sq.Expr("now()")
+	}
+	artifacts := release.Artifacts
+	if artifacts == nil {
+		artifacts = []string{}
+	}
+
+	var id int64
+	err := sq.Insert("releases").
+		Columns("project_id", "version", "released_at", "notes", "artifacts").
+		Values(release.ProjectID, release.Version, releasedAt, release.Notes, pq.StringArray(artifacts)).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx).Scan(&id)
+	if err != nil {
+		return nil, dbError("repository.CreateRelease", err)
+	}
+	if err := repo.syncProjectVersion(ctx, release.ProjectID); err != nil {
+		return nil, dbError("repository.CreateRelease", err)
+	}
+	return repo.GetRelease(ctx, release.ProjectID, id)
+}
+
+func (repo *PortfolioRepository) GetRelease(ctx context.Context, projectID, id int64) (*models.Release, error) {
+	row := sq.Select(releaseColumns).
+		From("releases r").
+		Where(sq.Eq{"r.id": id, "r.project_id": projectID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx)
+	result, err := scanRelease(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("release_not_found", "Release with id %d not found in project %d", id, projectID)
+		}
+		return nil, dbError("repository.GetRelease", err)
+	}
+	return result, nil
+}
+
+func (repo *PortfolioRepository) ListReleases(ctx context.Context, projectID int64, filter *models.ReleaseFilter) ([]*models.Release, *models.PageInfo, error) {
+	result := []*models.Release{}
+
+	page, err := releaseSort.paginate(filter.SortKeys, filter.Cursor, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := filterReleases(sq.Select(releaseColumns).From("releases r"), projectID, filter)
+	rows, err := page.apply(query).PlaceholderFormat(sq.Dollar).RunWith(repo.Querier(ctx)).QueryContext(ctx)
+	if err != nil {
+		return nil, nil, dbError("repository.ListReleases", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		release, err := scanRelease(rows)
+		if err != nil {
+			return nil, nil, dbError("repository.ListReleases", err)
+		}
+		result = append(result, release)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, dbError("repository.ListReleases", err)
+	}
+
+	total, err := repo.count(ctx, filterReleases(sq.Select("COUNT(*)").From("releases r"), projectID, filter))
+	if err != nil {
+		return nil, nil, dbError("repository.ListReleases", err)
+	}
+	result, info := page.finish(result, total)
+	return result, info, nil
+}
+
+// filterReleases compares versions by their semver_key, so that e.g.
+// 1.10.0 is above 1.9.0 and 2.0.0-rc.1 below 2.0.0.
+func filterReleases(query sq.SelectBuilder, projectID int64, filter *models.ReleaseFilter) sq.SelectBuilder {
+	query = query.Where(sq.Eq{"r.project_id": projectID})
+	if filter.MinVersion != "" {
+		query = query.Where("r.version_key >= semver_key(?)", filter.MinVersion)
+	}
+	if filter.MaxVersion != "" {
+		query = query.Where("r.version_key <= semver_key(?)", filter.MaxVersion)
+	}
+	if filter.Prerelease != nil {
+		query = query.Where("(split_part(r.version, '+', 1) LIKE '%-%') = ?", *filter.Prerelease)
+	}
+	return query
+}
+
+// ListRecentReleases lists the releases of live projects matching filter,
+// the latest released first. Only the technology filters and Limit of
+// filter apply.
+func (repo *PortfolioRepository) ListRecentReleases(ctx context.Context, filter *models.ProjectFilter) ([]*models.ProjectRelease, error) {
+	result := []*models.ProjectRelease{}
+
+	query := sq.Select(releaseColumns, "p.title", "p.slug",
+		`ARRAY(SELECT t.name FROM project_tech pt
+			JOIN techs t ON t.id = pt.tech_id AND t.deleted_at IS NULL
+			WHERE pt.project_id = p.id ORDER BY t.name, t.id)`).
+		From("releases r").
+		Join("projects p ON p.id = r.project_id").
+		OrderBy("r.released_at DESC", "r.id DESC")
+	query = filterProjects(query, &models.ProjectFilter{
+		TechnologiesID:        filter.TechnologiesID,
+		TechMatch:             filter.TechMatch,
+		ExcludeTechnologiesID: filter.ExcludeTechnologiesID,
+	})
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	rows, err := query.PlaceholderFormat(sq.Dollar).RunWith(repo.Querier(ctx)).QueryContext(ctx)
+	if err != nil {
+		return nil, dbError("repository.ListRecentReleases", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var release models.ProjectRelease
+		var artifacts, technologies pq.StringArray
+		err := rows.Scan(&release.ID, &release.ProjectID, &release.Version, &release.VersionKey, &release.ReleasedAt,
+			&release.Notes, &artifacts, &release.CreatedAt, &release.UpdatedAt,
+			&release.ProjectTitle, &release.ProjectSlug, &technologies)
+		if err != nil {
+			return nil, dbError("repository.ListRecentReleases", err)
+		}
+		release.Artifacts = artifacts
+		release.Technologies = technologies
+		result = append(result, &release)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("repository.ListRecentReleases", err)
+	}
+	return result, nil
+}
+
+func (repo *PortfolioRepository) PatchRelease(ctx context.Context, projectID, id int64, update *models.ReleaseUpdate) error {
+	query := sq.Update("releases").
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id, "project_id": projectID}).
+		PlaceholderFormat(sq.Dollar)
+	if update.Version != nil {
+		query = query.Set("version", *update.Version)
+	}
+	if update.ReleasedAt != nil {
+		query = query.Set("released_at", *update.ReleasedAt)
+	}
+	if update.Notes != nil {
+		query = query.Set("notes", *update.Notes)
+	}
+	if update.Artifacts != nil {
+		artifacts := *update.Artifacts
+		if artifacts == nil {
+			artifacts = []string{}
+		}
+		query = query.Set("artifacts", pq.StringArray(artifacts))
+	}
+
+	res, err := query.RunWith(repo.Querier(ctx)).ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.PatchRelease", err)
+	}
+	if err := expectAffected(res, apperrors.NotFound("release_not_found", "Release with id %d not found in project %d", id, projectID)); err != nil {
+		return err
+	}
+	if err := repo.syncProjectVersion(ctx, projectID); err != nil {
+		return dbError("repository.PatchRelease", err)
+	}
+	return nil
+}
+
+// DeleteRelease deletes a release. When it was the last one, the project
+// keeps its version.
+func (repo *PortfolioRepository) DeleteRelease(ctx context.Context, projectID, id int64) error {
+	res, err := sq.Delete("releases").
+		Where(sq.Eq{"id": id, "project_id": projectID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.DeleteRelease", err)
+	}
+	if err := expectAffected(res, apperrors.NotFound("release_not_found", "Release with id %d not found in project %d", id, projectID)); err != nil {
+		return err
+	}
+	if err := repo.syncProjectVersion(ctx, projectID); err != nil {
+		return dbError("repository.DeleteRelease", err)
+	}
+	return nil
+}
+
+// addRelease adds a release of version to the project unless it exists.
+func (repo *PortfolioRepository) addRelease(ctx context.Context, projectID int64, version string) error {
+	_, err := sq.Insert("releases").
+		Columns("project_id", "version").
+		Values(projectID, version).
+		Suffix("ON CONFLICT (project_id, version) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	return err
+}
+
+// syncProjectVersion sets the version of a project to its latest release
+// by semver precedence.
+func (repo *PortfolioRepository) syncProjectVersion(ctx context.Context, projectID int64) error {
+	_, err := sq.Update("projects p").
+		Set("version", sq.Expr("r.version")).
+		Set("updated_at", sq.Expr("now()")).
+		Suffix(`FROM (
+			SELECT version FROM releases WHERE project_id = ? ORDER BY version_key DESC, id DESC LIMIT 1
+		) r WHERE p.id = ? AND p.version <> r.version`, projectID, projectID).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const revisionColumns = "r.id, r.entity, r.entity_id, r.action, r.user_id, u.email, r.api_key_id, k.name, r.before, r.after, r.created_at"
+
+func selectRevisions() sq.SelectBuilder {
+	return sq.Select(revisionColumns).
+		From("revisions r").
+		LeftJoin("users u ON u.id = r.user_id").
+		LeftJoin("api_keys k ON k.id = r.api_key_id")
+}
+
+func scanRevision(row sq.RowScanner) (*models.Revision, error) {
+	var revision models.Revision
+	var before, after []byte
+	err := row.Scan(&revision.ID, &revision.Entity, &revision.EntityID, &revision.Action,
+		&revision.Actor.UserID, &revision.Actor.UserEmail, &revision.Actor.APIKeyID, &revision.Actor.APIKeyName,
+		&before, &after, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	revision.Before, revision.After = before, after
+	return &revision, nil
+}
+
+// nullJSON stores an absent snapshot as NULL rather than as a JSON null.
+func nullJSON(data []byte) any {
+	if data == nil {
+		return nil
+	}
+	return string(data)
+}
+
+func (repo *PortfolioRepository) CreateRevision(ctx context.Context, revision *models.Revision) error {
+	_, err := sq.Insert("revisions").
+		Columns("entity", "entity_id", "action", "user_id", "api_key_id", "before", "after").
+		Values(revision.Entity, revision.EntityID, revision.Action, revision.Actor.UserID, revision.Actor.APIKeyID,
+			nullJSON(revision.Before), nullJSON(revision.After)).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		ExecContext(ctx)
+	if err != nil {
+		return dbError("repository.CreateRevision", err)
+	}
+	return nil
+}
+
+// ListRevisions returns the revisions of a record, newest first.
+func (repo *PortfolioRepository) ListRevisions(ctx context.Context, entity models.RevisionEntity, entityID int64) ([]*models.Revision, error) {
+	result := []*models.Revision{}
+	rows, err := selectRevisions().
+		Where(sq.Eq{"r.entity": entity, "r.entity_id": entityID}).
+		OrderBy("r.id DESC").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, dbError("repository.ListRevisions", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, dbError("repository.ListRevisions", err)
+		}
+		result = append(result, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("repository.ListRevisions", err)
+	}
+	return result, nil
+}
+
+func (repo *PortfolioRepository) GetRevision(ctx context.Context, entity models.RevisionEntity, entityID, id int64) (*models.Revision, error) {
+	row := selectRevisions().
+		Where(sq.Eq{"r.id": id, "r.entity": entity, "r.entity_id": entityID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(repo.Querier(ctx)).
+		QueryRowContext(ctx)
+	result, err := scanRevision(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("revision_not_found", "Revision with id %d not found for %s %d", id, entity, entityID)
+		}
+		return nil, dbError("repository.GetRevision", err)
+	}
+	return result, nil
+}
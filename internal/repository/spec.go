@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"slices"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// sortColumn is a sortable field: the SQL expression it orders by and how
+// to read the same value back from a scanned item for keyset cursors.
+type sortColumn[T any] struct {
+	expr  string
+	value func(T) any
+}
+
+// sortSpec whitelists the fields a list can be sorted by. Only column
+// expressions from the spec ever reach the ORDER BY clause.
+type sortSpec[T any] struct {
+	// columns maps API field names to sortable columns.
+	columns map[string]sortColumn[T]
+	// defaults are used when the client asks for no particular order.
+	defaults []models.SortKey
+	// tieBreaker is appended to every order so paging is deterministic.
+	tieBreaker sortColumn[T]
+}
+
+var projectSort = sortSpec[*models.Project]{
+	columns: map[string]sortColumn[*models.Project]{
+		"id":            {"p.id", func(p *models.Project) any { return p.ID }},
+		"title":         {"p.title", func(p *models.Project) any { return p.Title }},
+		"version":       {"p.version_key", func(p *models.Project) any { return p.VersionKey }},
+		"is_active":     {"p.is_active", func(p *models.Project) any { return p.IsActive.Bool }},
+		"is_archived":   {"p.is_archived", func(p *models.Project) any { return p.IsArchived.Bool }},
+		"is_developing": {"p.is_developing", func(p *models.Project) any { return p.IsDeveloping.Bool }},
+		"created_at":    {"p.created_at", func(p *models.Project) any { return p.CreatedAt }},
+		"updated_at":    {"p.updated_at", func(p *models.Project) any { return p.UpdatedAt }},
+		// relevance is only selected by searchProjects.
+		models.SortRelevance: {"p.rank", func(p *models.Project) any { return p.Search.Rank }},
+	},
+	defaults:   []models.SortKey{{Field: "title"}},
+	tieBreaker: sortColumn[*models.Project]{"p.id", func(p *models.Project) any { return p.ID }},
+}
+
+var technologySort = sortSpec[*models.Technology]{
+	columns: map[string]sortColumn[*models.Technology]{
+		"id":         {"t.id", func(t *models.Technology) any { return t.ID }},
+		"name":       {"t.name", func(t *models.Technology) any { return t.Name }},
+		"created_at": {"t.created_at", func(t *models.Technology) any { return t.CreatedAt }},
+		"updated_at": {"t.updated_at", func(t *models.Technology) any { return t.UpdatedAt }},
+	},
+	defaults:   []models.SortKey{{Field: "name"}},
+	tieBreaker: sortColumn[*models.Technology]{"t.id", func(t *models.Technology) any { return t.ID }},
+}
+
+var releaseSort = sortSpec[*models.Release]{
+	columns: map[string]sortColumn[*models.Release]{
+		"id":          {"r.id", func(r *models.Release) any { return r.ID }},
+		"version":     {"r.version_key", func(r *models.Release) any { return r.VersionKey }},
+		"released_at": {"r.released_at", func(r *models.Release) any { return r.ReleasedAt }},
+		"created_at":  {"r.created_at", func(r *models.Release) any { return r.CreatedAt }},
+		"updated_at":  {"r.updated_at", func(r *models.Release) any { return r.UpdatedAt }},
+	},
+	defaults:   []models.SortKey{{Field: "version", Desc: true}},
+	tieBreaker: sortColumn[*models.Release]{"r.id", func(r *models.Release) any { return r.ID }},
+}
+
+// resolve validates keys against the spec, falling back to the defaults.
+func (s sortSpec[T]) resolve(keys []models.SortKey) ([]models.SortKey, error) {
+	if len(keys) == 0 {
+		return s.defaults, nil
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if _, ok := s.columns[key.Field]; !ok {
+			return nil, apperrors.BadRequest("invalid_sort_field",
+				"Unknown sort field %q, allowed fields: %s", key.Field, strings.Join(s.fields(), ", "))
+		}
+		if seen[key.Field] {
+			return nil, apperrors.BadRequest("invalid_sort_field", "Sort field %q is repeated", key.Field)
+		}
+		seen[key.Field] = true
+	}
+	return keys, nil
+}
+
+func (s sortSpec[T]) fields() []string {
+	fields := make([]string, 0, len(s.columns))
+	for field := range s.columns {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// page is a validated request for one page of a list.
+type page[T any] struct {
+	spec   sortSpec[T]
+	keys   []models.SortKey
+	after  []any
+	limit  uint64
+	offset uint64
+}
+
+// paginate validates the requested sort and cursor. A cursor switches the
+// page from offset to keyset mode and carries the sort it was issued for.
+func (s sortSpec[T]) paginate(sortKeys func() ([]models.SortKey, error), encodedCursor string, limit, offset uint64) (*page[T], error) {
+	keys, err := sortKeys()
+	if err != nil {
+		return nil, apperrors.BadRequest("invalid_sort", "%s", err.Error())
+	}
+	keys, err = s.resolve(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &page[T]{spec: s, keys: keys, limit: limit, offset: offset}
+	if encodedCursor == "" {
+		return p, nil
+	}
+	if offset > 0 {
+		return nil, apperrors.BadRequest("invalid_cursor", "Cursor and offset can't be used together")
+	}
+	c, err := decodeCursor(encodedCursor)
+	if err != nil || len(c.Values) != len(keys)+1 {
+		return nil, apperrors.BadRequest("invalid_cursor", "Cursor is malformed")
+	}
+	if c.Sort != models.FormatSort(keys) {
+		return nil, apperrors.BadRequest("invalid_cursor", "Cursor was issued for sort %q", c.Sort)
+	}
+	p.after = c.Values
+	return p, nil
+}
+
+// apply adds the keyset condition, ORDER BY, LIMIT and OFFSET to query.
+// One extra row is fetched to find out whether there is a next page.
+func (p *page[T]) apply(query sq.SelectBuilder) sq.SelectBuilder {
+	if p.after != nil {
+		query = query.Where(p.keysetCondition())
+	}
+
+	orderBy := make([]string, 0, len(p.keys)+1)
+	for _, key := range p.keys {
+		column := p.spec.columns[key.Field]
+		if key.Desc {
+			orderBy = append(orderBy, column.expr+" DESC")
+		} else {
+			orderBy = append(orderBy, column.expr+" ASC")
+		}
+	}
+	query = query.OrderBy(append(orderBy, p.spec.tieBreaker.expr+" ASC")...)
+
+	if p.limit > 0 {
+		query = query.Limit(p.limit + 1)
+	}
+	if p.offset > 0 {
+		query = query.Offset(p.offset)
+	}
+	return query
+}
+
+// keysetCondition selects the rows strictly after the cursor position:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... with < for descending keys.
+func (p *page[T]) keysetCondition() sq.Sqlizer {
+	type key struct {
+		expr string
+		desc bool
+	}
+	keys := make([]key, 0, len(p.keys)+1)
+	for _, k := range p.keys {
+		keys = append(keys, key{p.spec.columns[k.Field].expr, k.Desc})
+	}
+	keys = append(keys, key{p.spec.tieBreaker.expr, false})
+
+	or := sq.Or{}
+	for i, k := range keys {
+		and := sq.And{}
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Eq{keys[j].expr: p.after[j]})
+		}
+		if k.desc {
+			and = append(and, sq.Lt{k.expr: p.after[i]})
+		} else {
+			and = append(and, sq.Gt{k.expr: p.after[i]})
+		}
+		or = append(or, and)
+	}
+	return or
+}
+
+// finish trims the extra row fetched by apply and builds the page info.
+func (p *page[T]) finish(items []T, total int64) ([]T, *models.PageInfo) {
+	info := &models.PageInfo{Total: total, Limit: p.limit, Offset: p.offset}
+	if p.limit == 0 || uint64(len(items)) <= p.limit {
+		return items, info
+	}
+
+	items = items[:p.limit]
+	last := items[len(items)-1]
+	values := make([]any, 0, len(p.keys)+1)
+	for _, key := range p.keys {
+		values = append(values, p.spec.columns[key.Field].value(last))
+	}
+	values = append(values, p.spec.tieBreaker.value(last))
+	info.NextCursor = encodeCursor(cursor{Sort: models.FormatSort(p.keys), Values: values})
+	return items, info
+}
+
+// cursor is the opaque keyset position handed to clients.
+type cursor struct {
+	Sort   string `json:"s"`
+	Values []any  `json:"v"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return c, err
+	}
+	for _, value := range c.Values {
+		switch value.(type) {
+		case string, bool, json.Number:
+		default:
+			return c, errors.New("unsupported cursor value")
+		}
+	}
+	return c, nil
+}
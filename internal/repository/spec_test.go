@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"slices"
+	"strings"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func sortKeysOf(expr string) func() ([]models.SortKey, error) {
+	return func() ([]models.SortKey, error) {
+		return models.ParseSort(expr)
+	}
+}
+
+func TestSortSpecResolve(t *testing.T) {
+	keys, err := projectSort.resolve(nil)
+	if err != nil || !slices.Equal(keys, projectSort.defaults) {
+		t.Errorf("resolve(nil) = %v, %v, want the defaults", keys, err)
+	}
+
+	keys = []models.SortKey{{Field: "is_active", Desc: true}, {Field: "version"}}
+	if got, err := projectSort.resolve(keys); err != nil || !slices.Equal(got, keys) {
+		t.Errorf("resolve(%v) = %v, %v", keys, got, err)
+	}
+
+	invalid := map[string][]models.SortKey{
+		"unknown field":  {{Field: "title"}, {Field: "password"}},
+		"column name":    {{Field: "p.title"}},
+		"injection":      {{Field: "title; DROP TABLE projects"}},
+		"repeated field": {{Field: "title"}, {Field: "title", Desc: true}},
+	}
+	for name, keys := range invalid {
+		_, err := projectSort.resolve(keys)
+		if !errors.Is(err, apperrors.ErrBadRequest) {
+			t.Errorf("%s: resolve(%v) error = %v, want a bad request", name, keys, err)
+		}
+	}
+
+	// Fields are only valid for their own list.
+	if _, err := technologySort.resolve([]models.SortKey{{Field: "title"}}); err == nil {
+		t.Error("technologySort accepted the project field title")
+	}
+}
+
+func TestPageOrderBy(t *testing.T) {
+	tests := []struct {
+		sort string
+		want string
+	}{
+		{sort: "", want: "ORDER BY p.title ASC, p.id ASC"},
+		{sort: "-is_active,version", want: "ORDER BY p.is_active DESC, p.version_key ASC, p.id ASC"},
+		{sort: "-id", want: "ORDER BY p.id DESC, p.id ASC"},
+	}
+	for _, tt := range tests {
+		p, err := projectSort.paginate(sortKeysOf(tt.sort), "", 10, 20)
+		if err != nil {
+			t.Fatalf("paginate(%q) error = %v", tt.sort, err)
+		}
+		query, _, err := p.apply(sq.Select("p.id").From("projects p")).ToSql()
+		if err != nil {
+			t.Fatalf("ToSql() error = %v", err)
+		}
+		if !strings.Contains(query, tt.want+" LIMIT 11 OFFSET 20") {
+			t.Errorf("sort %q: query = %q, want %q", tt.sort, query, tt.want)
+		}
+	}
+
+	if _, err := projectSort.paginate(sortKeysOf("title,"), "", 10, 0); !errors.Is(err, apperrors.ErrBadRequest) {
+		t.Errorf("paginate with a malformed sort error = %v, want a bad request", err)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	p, err := projectSort.paginate(sortKeysOf("-is_active,title"), "", 2, 0)
+	if err != nil {
+		t.Fatalf("paginate() error = %v", err)
+	}
+	items := []*models.Project{
+		{ID: 3, Title: "A"},
+		{ID: 9, Title: "B \"quoted\""},
+		{ID: 1, Title: "C"},
+	}
+	items[1].IsActive.SetValid(true)
+	got, info := p.finish(items, 10)
+	if len(got) != 2 || info.Total != 10 || info.NextCursor == "" {
+		t.Fatalf("finish() = %d items, %+v, want 2 items and a cursor", len(got), info)
+	}
+
+	next, err := projectSort.paginate(sortKeysOf("-is_active,title"), info.NextCursor, 2, 0)
+	if err != nil {
+		t.Fatalf("paginate(cursor) error = %v", err)
+	}
+	want := []string{"true", `B "quoted"`, "9"}
+	if len(next.after) != len(want) {
+		t.Fatalf("cursor values = %v, want %v", next.after, want)
+	}
+	for i, value := range next.after {
+		if got := fmt.Sprint(value); got != want[i] {
+			t.Errorf("cursor value %d = %q, want %q", i, got, want[i])
+		}
+	}
+
+	// The last page has no cursor.
+	if _, info := next.finish(items[2:], 10); info.NextCursor != "" {
+		t.Errorf("last page cursor = %q, want none", info.NextCursor)
+	}
+}
+
+func TestCursorRejected(t *testing.T) {
+	valid := encodeCursor(cursor{Sort: "-is_active,title", Values: []any{true, "B", 9}})
+	tests := []struct {
+		name   string
+		sort   string
+		cursor string
+		offset uint64
+	}{
+		{name: "other sort", sort: "title", cursor: valid},
+		{name: "other direction", sort: "is_active,title", cursor: valid},
+		{name: "with offset", sort: "-is_active,title", cursor: valid, offset: 10},
+		{name: "not base64", sort: "-is_active,title", cursor: "not a cursor!"},
+		{name: "not json", sort: "-is_active,title", cursor: base64.RawURLEncoding.EncodeToString([]byte("[1,2"))},
+		{name: "too few values", sort: "-is_active,title", cursor: encodeCursor(cursor{Sort: "-is_active,title", Values: []any{true, "B"}})},
+		{name: "too many values", sort: "-is_active,title", cursor: encodeCursor(cursor{Sort: "-is_active,title", Values: []any{true, "B", 9, 10}})},
+		{name: "object value", sort: "-is_active,title", cursor: encodeCursor(cursor{Sort: "-is_active,title", Values: []any{true, map[string]any{"a": 1}, 9}})},
+		{name: "array value", sort: "-is_active,title", cursor: encodeCursor(cursor{Sort: "-is_active,title", Values: []any{true, []any{"B"}, 9}})},
+		{name: "null value", sort: "-is_active,title", cursor: encodeCursor(cursor{Sort: "-is_active,title", Values: []any{true, nil, 9}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := projectSort.paginate(sortKeysOf(tt.sort), tt.cursor, 2, tt.offset)
+			if !errors.Is(err, apperrors.ErrBadRequest) {
+				t.Errorf("paginate() error = %v, want a bad request", err)
+			}
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	c := encodeCursor(cursor{Sort: "-is_active,title", Values: []any{true, "B", 9}})
+	p, err := projectSort.paginate(sortKeysOf("-is_active,title"), c, 2, 0)
+	if err != nil {
+		t.Fatalf("paginate() error = %v", err)
+	}
+	query, args, err := p.apply(sq.Select("p.id").From("projects p")).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+	// Descending keys continue below the cursor, ascending ones and the
+	// id tiebreaker above it.
+	want := "WHERE ((p.is_active < ?) OR (p.is_active = ? AND p.title > ?) OR (p.is_active = ? AND p.title = ? AND p.id > ?))" +
+		" ORDER BY p.is_active DESC, p.title ASC, p.id ASC LIMIT 3"
+	if !strings.HasSuffix(query, want) {
+		t.Errorf("query = %q, want it to end with %q", query, want)
+	}
+	wantArgs := []string{"true", "true", "B", "true", "B", "9"}
+	if len(args) != len(wantArgs) {
+		t.Fatalf("args = %v, want %v", args, wantArgs)
+	}
+	for i, arg := range args {
+		if got := fmt.Sprint(arg); got != wantArgs[i] {
+			t.Errorf("arg %d = %q, want %q", i, got, wantArgs[i])
+		}
+	}
+}
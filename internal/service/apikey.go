@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"strings"
+	"time"
+)
+
+// apiKeyPrefix marks our keys so they are easy to spot in leaked logs and by
+// secret scanners.
+const apiKeyPrefix = "gw_"
+
+type APIKeyConfig struct {
+	QuotaWindow  time.Duration `env:"API_KEY_QUOTA_WINDOW" env-default:"1h"`
+	DefaultQuota int           `env:"API_KEY_DEFAULT_QUOTA" env-default:"1000"`
+}
+
+type APIKeyRepo interface {
+	CreateAPIKey(ctx context.Context, key *models.APIKey) (*models.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+	UseAPIKey(ctx context.Context, keyHash string, window time.Duration) (*models.APIKey, error)
+}
+
+type APIKeyService struct {
+	apiKeyRepo APIKeyRepo
+	cfg        APIKeyConfig
+}
+
+func NewAPIKeyService(repo APIKeyRepo, cfg APIKeyConfig) *APIKeyService {
+	return &APIKeyService{apiKeyRepo: repo, cfg: cfg}
+}
+
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, request *models.APIKeyRequest) (*models.CreatedAPIKey, error) {
+	for _, scope := range request.Scopes {
+		if !scope.IsValid() {
+			return nil, apperrors.Validation("invalid_scope", "Unknown scope %q", scope)
+		}
+	}
+	if request.ExpiresAt != nil && request.ExpiresAt.Before(time.Now()) {
+		return nil, apperrors.Validation("invalid_expiry", "Expiry must be in the future")
+	}
+	quota := s.cfg.DefaultQuota
+	if request.Quota != nil {
+		if *request.Quota < 0 {
+			return nil, apperrors.Validation("invalid_quota", "Quota must not be negative")
+		}
+		quota = *request.Quota
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + secret
+
+	apiKey := &models.APIKey{
+		Name:      request.Name,
+		Prefix:    key[:len(apiKeyPrefix)+6],
+		KeyHash:   hashToken(key),
+		Scopes:    request.Scopes,
+		Quota:     quota,
+		ExpiresAt: request.ExpiresAt,
+	}
+	if principal := models.PrincipalFromContext(ctx); principal != nil && principal.UserID != 0 {
+		apiKey.CreatedBy = &principal.UserID
+	}
+
+	created, err := s.apiKeyRepo.CreateAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CreatedAPIKey{APIKey: *created, Key: key}, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	return s.apiKeyRepo.ListAPIKeys(ctx)
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id int64) error {
+	return s.apiKeyRepo.RevokeAPIKey(ctx, id)
+}
+
+// AuthenticateAPIKey checks the key and counts the request against its
+// quota. The quota status is returned even when the quota is exhausted, so
+// callers can tell clients when to retry.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (*models.Principal, *models.QuotaStatus, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, nil, apperrors.Unauthorized("invalid_api_key", "API key is invalid, revoked or expired")
+	}
+
+	apiKey, err := s.apiKeyRepo.UseAPIKey(ctx, hashToken(key), s.cfg.QuotaWindow)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, nil, apperrors.Unauthorized("invalid_api_key", "API key is invalid, revoked or expired")
+		}
+		return nil, nil, err
+	}
+
+	var status *models.QuotaStatus
+	if apiKey.Quota > 0 {
+		status = &models.QuotaStatus{
+			Limit:     apiKey.Quota,
+			Remaining: max(apiKey.Quota-apiKey.QuotaUsed, 0),
+			Reset:     apiKey.QuotaWindowStart.Add(s.cfg.QuotaWindow),
+		}
+		if apiKey.QuotaUsed > apiKey.Quota {
+			return nil, status, apperrors.RateLimited("quota_exceeded", "API key quota of %d requests is exhausted", apiKey.Quota)
+		}
+	}
+
+	return &models.Principal{APIKeyID: apiKey.ID, Scopes: apiKey.Scopes}, status, nil
+}
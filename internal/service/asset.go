@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/internal/validation"
+	"gowebsite/pkg/logger"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"go.uber.org/zap"
+)
+
+type AssetConfig struct {
+	MaxAssetSize int64 `env:"ASSET_MAX_SIZE" env-default:"10485760"`
+	// AllowedAssetTypes are the MIME types uploads may have, as sniffed
+	// from their content rather than as claimed by the client.
+	AllowedAssetTypes []string `env:"ASSET_ALLOWED_TYPES" env-default:"image/png,image/jpeg,image/gif,image/webp,image/avif"`
+}
+
+// FileStorage keeps the files of project assets, see storage.Storage.
+type FileStorage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// sniffLen is how much of an upload is read to detect its type.
+const sniffLen = 3072
+
+// UploadAsset stores file as a new asset of the project.
+func (s *PortfolioService) UploadAsset(ctx context.Context, projectID int64, upload *models.AssetUpload, file io.Reader) (*models.ProjectAsset, error) {
+	if err := validation.Struct(upload); err != nil {
+		return nil, err
+	}
+	if upload.Size > s.assetCfg.MaxAssetSize {
+		return nil, apperrors.TooLarge("asset_too_large", "Asset must not be larger than %d bytes", s.assetCfg.MaxAssetSize)
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, apperrors.BadRequest("invalid_file", "Failed to read the uploaded file")
+	}
+	head = head[:n]
+	detected := mimetype.Detect(head)
+	contentType, ok := s.allowedType(detected)
+	if !ok {
+		return nil, apperrors.MediaType("unsupported_asset_type", "Asset type %s is not allowed, allowed types: %s",
+			detected.String(), strings.Join(s.assetCfg.AllowedAssetTypes, ", "))
+	}
+
+	if _, err := s.portfolioRepo.GetProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	name, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("projects/%d/%s%s", projectID, name, detected.Extension())
+	if err := s.storage.Put(ctx, key, io.MultiReader(bytes.NewReader(head), file), upload.Size, contentType); err != nil {
+		return nil, fmt.Errorf("service.UploadAsset: %w", err)
+	}
+
+	var asset *models.ProjectAsset
+	err = s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		assets, err := s.portfolioRepo.ListProjectAssets(ctx, projectID, true)
+		if err != nil {
+			return err
+		}
+		asset, err = s.portfolioRepo.CreateAsset(ctx, &models.ProjectAsset{
+			ProjectID:   projectID,
+			StorageKey:  key,
+			Filename:    upload.Filename,
+			ContentType: contentType,
+			Size:        upload.Size,
+			Caption:     upload.Caption,
+			Position:    len(assets),
+		})
+		if err != nil {
+			return err
+		}
+		if upload.Position == nil || *upload.Position >= len(assets) {
+			return nil
+		}
+		asset.Position = *upload.Position
+		return s.portfolioRepo.SetAssetPositions(ctx, projectID, assetIDs(slices.Insert(assets, asset.Position, asset)))
+	})
+	if err != nil {
+		s.deleteFiles(ctx, key)
+		return nil, err
+	}
+
+	asset.URL = s.storage.URL(asset.StorageKey)
+	return asset, nil
+}
+
+// allowedType returns the allowed type detected is, or is an alias of.
+func (s *PortfolioService) allowedType(detected *mimetype.MIME) (string, bool) {
+	for _, allowed := range s.assetCfg.AllowedAssetTypes {
+		if detected.Is(allowed) {
+			return allowed, true
+		}
+	}
+	return "", false
+}
+
+func (s *PortfolioService) ListAssets(ctx context.Context, projectID int64) ([]*models.ProjectAsset, error) {
+	project, err := s.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return project.Assets, nil
+}
+
+// OpenAsset opens the file of an asset. The caller closes it.
+func (s *PortfolioService) OpenAsset(ctx context.Context, asset *models.ProjectAsset) (io.ReadCloser, error) {
+	file, err := s.storage.Get(ctx, asset.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("service.OpenAsset: %w", err)
+	}
+	return file, nil
+}
+
+// PatchAsset changes the caption of an asset and moves it to another
+// position, shifting the assets in between.
+func (s *PortfolioService) PatchAsset(ctx context.Context, projectID, assetID int64, update *models.AssetUpdate) (*models.ProjectAsset, error) {
+	if err := validation.Struct(update); err != nil {
+		return nil, err
+	}
+
+	var asset *models.ProjectAsset
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.portfolioRepo.GetProject(ctx, projectID); err != nil {
+			return err
+		}
+		assets, err := s.portfolioRepo.ListProjectAssets(ctx, projectID, true)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(assets, func(a *models.ProjectAsset) bool { return a.ID == assetID })
+		if i < 0 {
+			return apperrors.NotFound("asset_not_found", "Asset with id %d not found in project %d", assetID, projectID)
+		}
+		if update.Caption != nil {
+			if err := s.portfolioRepo.UpdateAssetCaption(ctx, projectID, assetID, *update.Caption); err != nil {
+				return err
+			}
+		}
+		if update.Position != nil {
+			moved := assets[i]
+			assets = slices.Delete(assets, i, i+1)
+			assets = slices.Insert(assets, min(*update.Position, len(assets)), moved)
+			if err := s.portfolioRepo.SetAssetPositions(ctx, projectID, assetIDs(assets)); err != nil {
+				return err
+			}
+		}
+		asset, err = s.portfolioRepo.GetAsset(ctx, projectID, assetID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	asset.URL = s.storage.URL(asset.StorageKey)
+	return asset, nil
+}
+
+// DeleteAsset deletes an asset and its file, closing the gap it leaves in
+// the order.
+func (s *PortfolioService) DeleteAsset(ctx context.Context, projectID, assetID int64) error {
+	var key string
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.portfolioRepo.GetProject(ctx, projectID); err != nil {
+			return err
+		}
+		assets, err := s.portfolioRepo.ListProjectAssets(ctx, projectID, true)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(assets, func(a *models.ProjectAsset) bool { return a.ID == assetID })
+		if i < 0 {
+			return apperrors.NotFound("asset_not_found", "Asset with id %d not found in project %d", assetID, projectID)
+		}
+		key = assets[i].StorageKey
+		if err := s.portfolioRepo.DeleteAsset(ctx, projectID, assetID); err != nil {
+			return err
+		}
+		return s.portfolioRepo.SetAssetPositions(ctx, projectID, assetIDs(slices.Delete(assets, i, i+1)))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deleteFiles(ctx, key)
+	return nil
+}
+
+// deleteFiles removes stored files whose rows are gone. A failure only
+// leaves an orphaned file behind, so it is logged rather than returned.
+func (s *PortfolioService) deleteFiles(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(context.WithoutCancel(ctx), key); err != nil {
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "Failed to delete asset file", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+func (s *PortfolioService) setAssetURLs(projects ...*models.Project) {
+	for _, project := range projects {
+		for _, asset := range project.Assets {
+			asset.URL = s.storage.URL(asset.StorageKey)
+		}
+	}
+}
+
+func assetIDs(assets []*models.ProjectAsset) []int64 {
+	ids := make([]int64, len(assets))
+	for i, asset := range assets {
+		ids[i] = asset.ID
+	}
+	return ids
+}
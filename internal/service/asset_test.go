@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// memStorage keeps files in memory.
+type memStorage struct {
+	files map[string][]byte
+}
+
+func (s *memStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.files[key] = content
+	return nil
+}
+
+func (s *memStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	content, ok := s.files[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (s *memStorage) Delete(ctx context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memStorage) URL(key string) string {
+	return "/media/" + key
+}
+
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00\x1f\x15\xc4\x89"
+
+func TestUploadAsset(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		// wantErr is nil for uploads that are stored.
+		wantErr error
+	}{
+		{name: "png", filename: "screenshot.png", content: pngHeader + strings.Repeat("\x00", 5000)},
+		{name: "html named png", filename: "screenshot.png", content: "<!DOCTYPE html><html><script>alert(1)</script></html>", wantErr: apperrors.ErrMediaType},
+		{name: "html after a png signature", filename: "screenshot.png", content: "<html>\x89PNG\r\n\x1a\n</html>", wantErr: apperrors.ErrMediaType},
+		{name: "svg named png", filename: "logo.png", content: `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`, wantErr: apperrors.ErrMediaType},
+		{name: "pdf named jpg", filename: "photo.jpg", content: "%PDF-1.7\n", wantErr: apperrors.ErrMediaType},
+		{name: "empty", filename: "empty.png", content: "", wantErr: apperrors.ErrBadRequest},
+		{name: "too large", filename: "big.png", content: pngHeader + strings.Repeat("\x00", 1<<12), wantErr: apperrors.ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			project := repo.addProject(storedProject("Site"))
+			storage := &memStorage{files: map[string][]byte{}}
+			cfg := AssetConfig{MaxAssetSize: 6000, AllowedAssetTypes: []string{"image/png", "image/jpeg"}}
+			s := NewPortfolioService(repo, storage, cfg)
+
+			size := int64(len(tt.content))
+			if tt.wantErr == apperrors.ErrTooLarge {
+				size = cfg.MaxAssetSize + 1
+			}
+			upload := &models.AssetUpload{Filename: tt.filename, Size: size}
+			asset, err := s.UploadAsset(context.Background(), project.ID, upload, strings.NewReader(tt.content))
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("UploadAsset() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(storage.files) != 0 || len(repo.assets) != 0 {
+					t.Errorf("rejected upload stored %v and %d assets", slices.Collect(maps.Keys(storage.files)), len(repo.assets))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UploadAsset() error = %v", err)
+			}
+			if asset.ContentType != "image/png" || !strings.HasSuffix(asset.StorageKey, ".png") {
+				t.Errorf("asset = %+v, want a png", asset)
+			}
+			if asset.URL != "/media/"+asset.StorageKey {
+				t.Errorf("url = %q", asset.URL)
+			}
+			if got := string(storage.files[asset.StorageKey]); got != tt.content {
+				t.Errorf("stored %d bytes, want the %d uploaded", len(got), len(tt.content))
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const jwtIssuer = "gowebsite"
+
+type AuthConfig struct {
+	JWTSecret       string        `env:"AUTH_JWT_SECRET" json:"-"`
+	AccessTokenTTL  time.Duration `env:"AUTH_ACCESS_TOKEN_TTL" env-default:"15m"`
+	RefreshTokenTTL time.Duration `env:"AUTH_REFRESH_TOKEN_TTL" env-default:"720h"`
+	// The admin account is created or updated on startup when both are set.
+	AdminEmail    string `env:"AUTH_ADMIN_EMAIL"`
+	AdminPassword string `env:"AUTH_ADMIN_PASSWORD" json:"-"`
+}
+
+type AuthRepo interface {
+	CreateUser(ctx context.Context, user *models.User) (int64, error)
+	GetUser(ctx context.Context, id int64) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	UpdateUserPassword(ctx context.Context, id int64, passwordHash string, role models.Role) error
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, tokenHash string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID int64) error
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type AuthService struct {
+	authRepo AuthRepo
+	cfg      AuthConfig
+	// dummyHash is compared against when the user doesn't exist, so login
+	// takes the same time for unknown emails and wrong passwords.
+	dummyHash []byte
+}
+
+func NewAuthService(repo AuthRepo, cfg AuthConfig) *AuthService {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return &AuthService{authRepo: repo, cfg: cfg, dummyHash: dummyHash}
+}
+
+type accessClaims struct {
+	Role models.Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+var errInvalidCredentials = apperrors.Unauthorized("invalid_credentials", "Invalid email or password")
+
+func (s *AuthService) Login(ctx context.Context, credentials *models.Credentials) (*models.TokenPair, error) {
+	user, err := s.authRepo.GetUserByEmail(ctx, strings.ToLower(strings.TrimSpace(credentials.Email)))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			_ = bcrypt.CompareHashAndPassword(s.dummyHash, []byte(credentials.Password))
+			return nil, errInvalidCredentials
+		}
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password)); err != nil {
+		return nil, errInvalidCredentials
+	}
+	return s.issueTokens(ctx, user)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Refresh tokens are
+// single use; presenting a revoked one revokes every token of its user, since
+// it means the token was stolen or replayed.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	var tokens *models.TokenPair
+	var reused bool
+	var reusedUserID int64
+	err := s.authRepo.WithTx(ctx, func(ctx context.Context) error {
+		token, err := s.authRepo.GetRefreshTokenForUpdate(ctx, hashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return apperrors.Unauthorized("invalid_refresh_token", "Refresh token is invalid")
+			}
+			return err
+		}
+		if token.RevokedAt != nil {
+			reused, reusedUserID = true, token.UserID
+			return apperrors.Unauthorized("invalid_refresh_token", "Refresh token is invalid")
+		}
+		if time.Now().After(token.ExpiresAt) {
+			return apperrors.Unauthorized("refresh_token_expired", "Refresh token has expired")
+		}
+		if err := s.authRepo.RevokeRefreshToken(ctx, token.TokenHash); err != nil {
+			return err
+		}
+
+		user, err := s.authRepo.GetUser(ctx, token.UserID)
+		if err != nil {
+			return err
+		}
+		tokens, err = s.issueTokens(ctx, user)
+		return err
+	})
+	if reused {
+		if revokeErr := s.authRepo.RevokeUserRefreshTokens(ctx, reusedUserID); revokeErr != nil {
+			return nil, revokeErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	return s.authRepo.RevokeRefreshToken(ctx, hashToken(refreshToken))
+}
+
+// ParseAccessToken validates a signed access token and returns its caller.
+func (s *AuthService) ParseAccessToken(token string) (*models.Principal, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		return []byte(s.cfg.JWTSecret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(jwtIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, apperrors.Unauthorized("invalid_access_token", "Access token is invalid or expired").Wrap(err)
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, apperrors.Unauthorized("invalid_access_token", "Access token is invalid or expired").Wrap(err)
+	}
+	return &models.Principal{UserID: userID, Role: claims.Role}, nil
+}
+
+// EnsureAdmin creates the configured admin account, or resets its password
+// and role when it already exists. It does nothing when no admin is set.
+func (s *AuthService) EnsureAdmin(ctx context.Context) error {
+	if s.cfg.AdminEmail == "" || s.cfg.AdminPassword == "" {
+		return nil
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(s.cfg.AdminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("service.EnsureAdmin: %w", err)
+	}
+	email := strings.ToLower(strings.TrimSpace(s.cfg.AdminEmail))
+
+	return s.authRepo.WithTx(ctx, func(ctx context.Context) error {
+		user, err := s.authRepo.GetUserByEmail(ctx, email)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			_, err = s.authRepo.CreateUser(ctx, &models.User{Email: email, PasswordHash: string(passwordHash), Role: models.RoleAdmin})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		return s.authRepo.UpdateUserPassword(ctx, user.ID, string(passwordHash), models.RoleAdmin)
+	})
+}
+
+func (s *AuthService) issueTokens(ctx context.Context, user *models.User) (*models.TokenPair, error) {
+	now := time.Now()
+	claims := accessClaims{
+		Role: user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer,
+			Subject:   strconv.FormatInt(user.ID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.cfg.AccessTokenTTL)),
+		},
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.JWTSecret))
+	if err != nil {
+		return nil, fmt.Errorf("service.issueTokens: %w", err)
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("service.issueTokens: %w", err)
+	}
+	err = s.authRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.cfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.cfg.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the SHA-256 of a token. Tokens are random, so a fast
+// hash is enough to keep them unusable if the table leaks.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
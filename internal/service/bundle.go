@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/internal/validation"
+	"slices"
+	"time"
+
+	"github.com/volatiletech/null/v9"
+)
+
+// errDryRun rolls back the transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+// ExportBundle returns every live technology and project, in id order.
+func (s *PortfolioService) ExportBundle(ctx context.Context) (*models.Bundle, error) {
+	bundle := &models.Bundle{
+		Version:      models.BundleVersion,
+		ExportedAt:   time.Now().UTC().Truncate(time.Second),
+		Technologies: []*models.BundleTechnology{},
+		Projects:     []*models.BundleProject{},
+	}
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		technologies, _, err := s.portfolioRepo.ListTechnologies(ctx, &models.TechnologyFilter{Sort: "id"})
+		if err != nil {
+			return err
+		}
+		names := make(map[int64]string, len(technologies))
+		for _, technology := range technologies {
+			names[technology.ID] = technology.Name
+			bundleTechnology := toBundleTechnology(technology)
+			bundleTechnology.ID = technology.ID
+			bundle.Technologies = append(bundle.Technologies, bundleTechnology)
+		}
+
+		projects, _, err := s.portfolioRepo.ListProjects(ctx, &models.ProjectFilter{Sort: "id"})
+		if err != nil {
+			return err
+		}
+		for _, project := range projects {
+			bundleProject := toBundleProject(project, names)
+			bundleProject.ID = project.ID
+			bundle.Projects = append(bundle.Projects, bundleProject)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// ImportBundle creates and updates the technologies and projects of a
+// bundle, see ApplyBundle, and sums up what it did.
+func (s *PortfolioService) ImportBundle(ctx context.Context, bundle *models.Bundle, opts models.ImportOptions) (*models.ImportReport, error) {
+	if opts.Mode == "" {
+		opts.Mode = models.ImportMerge
+	}
+	plan, err := s.ApplyBundle(ctx, bundle, opts)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewImportReport(plan, opts), nil
+}
+
+// ApplyBundle makes the database match a bundle and returns the plan it
+// followed. Technologies are matched by name and projects by title, so the
+// ids of the exporting database don't matter; project technologies are
+// resolved by name against the bundle and the database. The plan is made
+// and applied in one transaction: it is applied completely or not at all,
+// and with DryRun not at all.
+func (s *PortfolioService) ApplyBundle(ctx context.Context, bundle *models.Bundle, opts models.ImportOptions) (*models.Plan, error) {
+	if !opts.Mode.IsValid() {
+		return nil, apperrors.BadRequest("invalid_import_mode", "Import mode must be %s or %s", models.ImportMerge, models.ImportReplace)
+	}
+	if bundle.Version != models.BundleVersion {
+		return nil, apperrors.BadRequest("unsupported_bundle_version", "Bundle version %d is not supported, expected %d", bundle.Version, models.BundleVersion)
+	}
+	if err := checkBundleNames(bundle); err != nil {
+		return nil, err
+	}
+
+	plan := &models.Plan{Steps: []*models.PlanStep{}}
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		technologyIDs, err := s.applyTechnologies(ctx, bundle.Technologies, opts.Mode, plan)
+		if err != nil {
+			return err
+		}
+		if err := s.applyProjects(ctx, bundle.Projects, technologyIDs, opts.Mode, plan); err != nil {
+			return err
+		}
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// checkBundleNames rejects bundles with empty entries or naming two
+// technologies or two projects the same, as they could not be told apart on
+// import.
+func checkBundleNames(bundle *models.Bundle) error {
+	var fields []apperrors.FieldError
+	seen := map[string]bool{}
+	for i, technology := range bundle.Technologies {
+		if technology == nil {
+			fields = append(fields, apperrors.FieldError{Field: fmt.Sprintf("technologies[%d]", i), Code: "required", Message: "is required"})
+			continue
+		}
+		if seen[technology.Name] {
+			fields = append(fields, apperrors.FieldError{
+				Field:   fmt.Sprintf("technologies[%d].name", i),
+				Code:    "unique",
+				Message: fmt.Sprintf("technology %q appears more than once", technology.Name),
+			})
+		}
+		seen[technology.Name] = true
+	}
+	seen = map[string]bool{}
+	for i, project := range bundle.Projects {
+		if project == nil {
+			fields = append(fields, apperrors.FieldError{Field: fmt.Sprintf("projects[%d]", i), Code: "required", Message: "is required"})
+			continue
+		}
+		if seen[project.Title] {
+			fields = append(fields, apperrors.FieldError{
+				Field:   fmt.Sprintf("projects[%d].title", i),
+				Code:    "unique",
+				Message: fmt.Sprintf("project %q appears more than once", project.Title),
+			})
+		}
+		seen[project.Title] = true
+	}
+	return apperrors.InvalidFields(fields)
+}
+
+// applyTechnologies applies the technologies of a bundle and returns the
+// ids of every live technology by name. When several live technologies
+// share a name, the oldest one is used.
+func (s *PortfolioService) applyTechnologies(ctx context.Context, technologies []*models.BundleTechnology, mode models.ImportMode, plan *models.Plan) (map[string]int64, error) {
+	existing, _, err := s.portfolioRepo.ListTechnologies(ctx, &models.TechnologyFilter{Sort: "id"})
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*models.Technology, len(existing))
+	ids := make(map[string]int64, len(existing)+len(technologies))
+	for _, technology := range existing {
+		if _, ok := byName[technology.Name]; !ok {
+			byName[technology.Name] = technology
+			ids[technology.Name] = technology.ID
+		}
+	}
+
+	inBundle := make(map[string]bool, len(technologies))
+	for i, bundleTechnology := range technologies {
+		inBundle[bundleTechnology.Name] = true
+		prefix := fmt.Sprintf("technologies[%d].", i)
+		target := &models.Technology{Name: bundleTechnology.Name, Svg: null.StringFromPtr(bundleTechnology.Svg)}
+		if err := prefixFields(validation.Struct(target), prefix); err != nil {
+			return nil, err
+		}
+		if err := prefixFields(sanitizeIcon(target), prefix); err != nil {
+			return nil, err
+		}
+		step := &models.PlanStep{Entity: models.RevisionTechnology, Name: target.Name}
+		plan.Steps = append(plan.Steps, step)
+
+		current, ok := byName[target.Name]
+		if !ok {
+			step.Action = models.PlanCreate
+			id, err := s.portfolioRepo.CreateTechnology(ctx, target)
+			if err != nil {
+				return nil, err
+			}
+			ids[target.Name] = id
+			if err := s.recordTechnology(ctx, models.RevisionCreate, id, nil, target); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		target.ID = current.ID
+		if slices.Equal(technologySnapshot(current), technologySnapshot(target)) {
+			step.Action = models.PlanKeep
+			continue
+		}
+		step.Action = models.PlanUpdate
+		step.Changes = bundleChanges(toBundleTechnology(current), toBundleTechnology(target))
+		if err := s.portfolioRepo.UpdateTechnology(ctx, target); err != nil {
+			return nil, err
+		}
+		if err := s.recordTechnology(ctx, models.RevisionUpdate, target.ID, current, target); err != nil {
+			return nil, err
+		}
+	}
+
+	if mode == models.ImportReplace {
+		for _, technology := range existing {
+			if inBundle[technology.Name] && byName[technology.Name] == technology {
+				continue
+			}
+			plan.Steps = append(plan.Steps, &models.PlanStep{Entity: models.RevisionTechnology, Action: models.PlanDelete, Name: technology.Name})
+			if err := s.portfolioRepo.DeleteTechnology(ctx, technology.ID); err != nil {
+				return nil, err
+			}
+			if err := s.recordTechnology(ctx, models.RevisionDelete, technology.ID, technology, nil); err != nil {
+				return nil, err
+			}
+			if ids[technology.Name] == technology.ID {
+				delete(ids, technology.Name)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// applyProjects applies the projects of a bundle, technologyIDs mapping
+// technology names to ids. When several live projects share a title, the
+// oldest one is used.
+func (s *PortfolioService) applyProjects(ctx context.Context, projects []*models.BundleProject, technologyIDs map[string]int64, mode models.ImportMode, plan *models.Plan) error {
+	existing, _, err := s.portfolioRepo.ListProjects(ctx, &models.ProjectFilter{Sort: "id"})
+	if err != nil {
+		return err
+	}
+	byTitle := make(map[string]*models.Project, len(existing))
+	for _, project := range existing {
+		if _, ok := byTitle[project.Title]; !ok {
+			byTitle[project.Title] = project
+		}
+	}
+	names := make(map[int64]string, len(technologyIDs))
+	for name, id := range technologyIDs {
+		names[id] = name
+	}
+
+	inBundle := make(map[string]bool, len(projects))
+	for i, bundleProject := range projects {
+		inBundle[bundleProject.Title] = true
+		prefix := fmt.Sprintf("projects[%d].", i)
+		target, err := fromBundleProject(bundleProject, technologyIDs, prefix)
+		if err != nil {
+			return err
+		}
+		if err := prefixFields(s.validateProject(ctx, target), prefix); err != nil {
+			return err
+		}
+		step := &models.PlanStep{Entity: models.RevisionProject, Name: target.Title}
+		plan.Steps = append(plan.Steps, step)
+
+		current, ok := byTitle[target.Title]
+		if !ok {
+			step.Action = models.PlanCreate
+			id, err := s.portfolioRepo.CreateProject(ctx, target)
+			if err != nil {
+				return err
+			}
+			after, err := s.portfolioRepo.GetProject(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := s.recordProject(ctx, models.RevisionCreate, id, nil, after); err != nil {
+				return err
+			}
+			continue
+		}
+
+		target.ID = current.ID
+		if target.Slug == "" {
+			target.Slug = current.Slug
+		}
+		if slices.Equal(projectSnapshot(current), projectSnapshot(target)) {
+			step.Action = models.PlanKeep
+			continue
+		}
+		step.Action = models.PlanUpdate
+		step.Changes = bundleChanges(toBundleProject(current, names), toBundleProject(target, names))
+		if err := s.portfolioRepo.UpdateProject(ctx, target); err != nil {
+			return err
+		}
+		after, err := s.portfolioRepo.GetProject(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.recordProject(ctx, models.RevisionUpdate, target.ID, current, after); err != nil {
+			return err
+		}
+	}
+
+	if mode == models.ImportReplace {
+		for _, project := range existing {
+			if inBundle[project.Title] && byTitle[project.Title] == project {
+				continue
+			}
+			plan.Steps = append(plan.Steps, &models.PlanStep{Entity: models.RevisionProject, Action: models.PlanDelete, Name: project.Title})
+			if err := s.portfolioRepo.DeleteProject(ctx, project.ID); err != nil {
+				return err
+			}
+			if err := s.recordProject(ctx, models.RevisionDelete, project.ID, project, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func toBundleTechnology(technology *models.Technology) *models.BundleTechnology {
+	return &models.BundleTechnology{Name: technology.Name, Svg: technology.Svg.Ptr()}
+}
+
+// toBundleProject converts a project to its bundle form, names mapping
+// technology ids to names. Technologies missing from names are left out.
+func toBundleProject(project *models.Project, names map[int64]string) *models.BundleProject {
+	result := &models.BundleProject{
+		Title:        project.Title,
+		Slug:         project.Slug,
+		Version:      project.Version,
+		Description:  project.Description,
+		IsActive:     project.IsActive.Bool,
+		IsArchived:   project.IsArchived.Bool,
+		IsDeveloping: project.IsDeveloping.Bool,
+		Links:        project.Links,
+		Technologies: []string{},
+	}
+	if result.Links == nil {
+		result.Links = []string{}
+	}
+	for _, id := range project.TechnologyIDs {
+		if name, ok := names[id]; ok {
+			result.Technologies = append(result.Technologies, name)
+		}
+	}
+	return result
+}
+
+// fromBundleProject converts a bundle project to a project, resolving its
+// technology names to ids.
+func fromBundleProject(project *models.BundleProject, technologyIDs map[string]int64, prefix string) (*models.Project, error) {
+	result := &models.Project{
+		Title:         project.Title,
+		Slug:          project.Slug,
+		Version:       project.Version,
+		Description:   project.Description,
+		IsActive:      null.BoolFrom(project.IsActive),
+		IsArchived:    null.BoolFrom(project.IsArchived),
+		IsDeveloping:  null.BoolFrom(project.IsDeveloping),
+		Links:         project.Links,
+		TechnologyIDs: make([]int64, 0, len(project.Technologies)),
+	}
+	if result.Links == nil {
+		result.Links = []string{}
+	}
+	var fields []apperrors.FieldError
+	for i, name := range project.Technologies {
+		id, ok := technologyIDs[name]
+		if !ok {
+			fields = append(fields, apperrors.FieldError{
+				Field:   fmt.Sprintf("%stechnologies[%d]", prefix, i),
+				Code:    "not_found",
+				Message: fmt.Sprintf("technology %q is neither in the bundle nor in the database", name),
+			})
+			continue
+		}
+		result.TechnologyIDs = append(result.TechnologyIDs, id)
+	}
+	if err := apperrors.InvalidFields(fields); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// bundleChanges lists the fields that differ between two records in bundle
+// form. Project technologies are compared as sets.
+func bundleChanges(before, after any) []*models.FieldChange {
+	for _, record := range []any{before, after} {
+		if project, ok := record.(*models.BundleProject); ok {
+			slices.Sort(project.Technologies)
+		}
+	}
+	beforeJSON, _ := json.Marshal(before)
+	afterJSON, _ := json.Marshal(after)
+	return diffSnapshots(beforeJSON, afterJSON)
+}
+
+// prefixFields prefixes the field errors of err with the position of the
+// record in the bundle.
+func prefixFields(err error, prefix string) error {
+	appErr, ok := apperrors.As(err)
+	if !ok || appErr.Fields == nil {
+		return err
+	}
+	fields := make([]apperrors.FieldError, len(appErr.Fields))
+	for i, field := range appErr.Fields {
+		field.Field = prefix + field.Field
+		fields[i] = field
+	}
+	return apperrors.InvalidFields(fields)
+}
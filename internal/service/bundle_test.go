@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/volatiletech/null/v9"
+)
+
+func bundleProject(title string, technologies ...string) *models.BundleProject {
+	return &models.BundleProject{
+		Title:        title,
+		Version:      "1.0.0",
+		IsActive:     true,
+		Links:        []string{},
+		Technologies: technologies,
+	}
+}
+
+func storedProject(title string, technologyIDs ...int64) *models.Project {
+	return &models.Project{
+		Title:         title,
+		Version:       "1.0.0",
+		IsActive:      null.BoolFrom(true),
+		IsArchived:    null.BoolFrom(false),
+		IsDeveloping:  null.BoolFrom(false),
+		Links:         []string{},
+		TechnologyIDs: technologyIDs,
+	}
+}
+
+// planSummary lists the steps of a plan as "action entity name".
+func planSummary(plan *models.Plan) []string {
+	summary := make([]string, len(plan.Steps))
+	for i, step := range plan.Steps {
+		summary[i] = string(step.Action) + " " + string(step.Entity) + " " + step.Name
+	}
+	return summary
+}
+
+func fieldNames(t *testing.T, err error) []string {
+	t.Helper()
+	appErr, ok := apperrors.As(err)
+	if !ok || !errors.Is(err, apperrors.ErrValidation) {
+		t.Fatalf("error = %v, want a validation error", err)
+	}
+	names := make([]string, len(appErr.Fields))
+	for i, field := range appErr.Fields {
+		names[i] = field.Field
+	}
+	return names
+}
+
+func TestApplyBundle(t *testing.T) {
+	tests := []struct {
+		name string
+		mode models.ImportMode
+		// bundle is applied to a database holding the technologies Go and
+		// PHP and the projects Kept (Go), Changed (Go) and Legacy (PHP).
+		bundle *models.Bundle
+		want   []string
+	}{
+		{
+			name: "merge creates, updates and keeps",
+			mode: models.ImportMerge,
+			bundle: &models.Bundle{
+				Version: models.BundleVersion,
+				Technologies: []*models.BundleTechnology{
+					{ID: 1, Name: "Go"},
+					{ID: 2, Name: "Rust"},
+				},
+				Projects: []*models.BundleProject{
+					bundleProject("Kept", "Go"),
+					bundleProject("Changed", "Go", "Rust"),
+					bundleProject("New", "Rust"),
+				},
+			},
+			want: []string{
+				"keep technology Go",
+				"create technology Rust",
+				"keep project Kept",
+				"update project Changed",
+				"create project New",
+			},
+		},
+		{
+			name: "replace deletes only unlisted records",
+			mode: models.ImportReplace,
+			bundle: &models.Bundle{
+				Version:      models.BundleVersion,
+				Technologies: []*models.BundleTechnology{{Name: "Go"}},
+				Projects:     []*models.BundleProject{bundleProject("Kept", "Go"), bundleProject("Changed", "Go")},
+			},
+			want: []string{
+				"keep technology Go",
+				"delete technology PHP",
+				"keep project Kept",
+				"keep project Changed",
+				"delete project Legacy",
+			},
+		},
+		{
+			name:   "merge leaves unlisted records alone",
+			mode:   models.ImportMerge,
+			bundle: &models.Bundle{Version: models.BundleVersion},
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			golang := repo.addTechnology("Go")
+			php := repo.addTechnology("PHP")
+			repo.addProject(storedProject("Kept", golang.ID))
+			repo.addProject(storedProject("Changed", golang.ID))
+			repo.addProject(storedProject("Legacy", php.ID))
+			s := NewPortfolioService(repo, nil, AssetConfig{})
+
+			plan, err := s.ApplyBundle(context.Background(), tt.bundle, models.ImportOptions{Mode: tt.mode})
+			if err != nil {
+				t.Fatalf("ApplyBundle() error = %v", err)
+			}
+			if got := planSummary(plan); !slices.Equal(got, tt.want) {
+				t.Errorf("plan = %q, want %q", got, tt.want)
+			}
+
+			deleted := map[string]bool{}
+			for _, step := range plan.Steps {
+				if step.Action == models.PlanDelete {
+					deleted[step.Name] = true
+				}
+			}
+			for _, technology := range repo.technologies {
+				if got := technology.DeletedAt != nil; got != deleted[technology.Name] {
+					t.Errorf("technology %s deleted = %v, want %v", technology.Name, got, deleted[technology.Name])
+				}
+			}
+			for _, project := range repo.projects {
+				if got := project.DeletedAt != nil; got != deleted[project.Title] {
+					t.Errorf("project %s deleted = %v, want %v", project.Title, got, deleted[project.Title])
+				}
+			}
+		})
+	}
+}
+
+// TestApplyBundleRemapsTechnologies applies a bundle exported from another
+// database: its technology ids are ignored and project technologies are
+// linked by name.
+func TestApplyBundleRemapsTechnologies(t *testing.T) {
+	repo := newFakeRepo()
+	golang := repo.addTechnology("Go")
+	s := NewPortfolioService(repo, nil, AssetConfig{})
+
+	bundle := &models.Bundle{
+		Version: models.BundleVersion,
+		Technologies: []*models.BundleTechnology{
+			{ID: golang.ID + 1, Name: "Go"},
+			{ID: golang.ID, Name: "Rust"},
+		},
+		Projects: []*models.BundleProject{bundleProject("Site", "Rust", "Go")},
+	}
+	if _, err := s.ApplyBundle(context.Background(), bundle, models.ImportOptions{Mode: models.ImportMerge}); err != nil {
+		t.Fatalf("ApplyBundle() error = %v", err)
+	}
+
+	rust := repo.technologyByName("Rust")
+	if rust == nil || rust.ID == golang.ID {
+		t.Fatalf("Rust = %+v, want a new technology", rust)
+	}
+	project := repo.projectByTitle("Site")
+	if project == nil {
+		t.Fatal("project Site was not created")
+	}
+	if want := []int64{rust.ID, golang.ID}; !slices.Equal(project.TechnologyIDs, want) {
+		t.Errorf("technology ids = %v, want %v", project.TechnologyIDs, want)
+	}
+	if project.Slug != "site" {
+		t.Errorf("slug = %q, want %q", project.Slug, "site")
+	}
+}
+
+func TestApplyBundleRejects(t *testing.T) {
+	tests := []struct {
+		name       string
+		bundle     *models.Bundle
+		wantFields []string
+	}{
+		{
+			name: "duplicate names",
+			bundle: &models.Bundle{
+				Version:      models.BundleVersion,
+				Technologies: []*models.BundleTechnology{{Name: "Go"}, {Name: "Go"}},
+				Projects:     []*models.BundleProject{bundleProject("Site"), bundleProject("Site")},
+			},
+			wantFields: []string{"technologies[1].name", "projects[1].title"},
+		},
+		{
+			name: "empty entries",
+			bundle: &models.Bundle{
+				Version:      models.BundleVersion,
+				Technologies: []*models.BundleTechnology{nil},
+				Projects:     []*models.BundleProject{bundleProject("Site"), nil},
+			},
+			wantFields: []string{"technologies[0]", "projects[1]"},
+		},
+		{
+			name: "invalid technology",
+			bundle: &models.Bundle{
+				Version:      models.BundleVersion,
+				Technologies: []*models.BundleTechnology{{Name: "Go"}, {Name: strings.Repeat("x", 101)}},
+			},
+			wantFields: []string{"technologies[1].name"},
+		},
+		{
+			name: "invalid icon",
+			bundle: &models.Bundle{
+				Version:      models.BundleVersion,
+				Technologies: []*models.BundleTechnology{{Name: "Go", Svg: new(string)}, {Name: "Rust", Svg: ptr("<html></html>")}},
+			},
+			wantFields: []string{"technologies[1].svg"},
+		},
+		{
+			name: "invalid project",
+			bundle: &models.Bundle{
+				Version:  models.BundleVersion,
+				Projects: []*models.BundleProject{bundleProject("Site"), {Title: "Broken", Version: "one", Links: []string{}}},
+			},
+			wantFields: []string{"projects[1].version"},
+		},
+		{
+			name: "unknown project technology",
+			bundle: &models.Bundle{
+				Version:      models.BundleVersion,
+				Technologies: []*models.BundleTechnology{{Name: "Go"}},
+				Projects:     []*models.BundleProject{bundleProject("Site", "Go", "Cobol")},
+			},
+			wantFields: []string{"projects[0].technologies[1]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.addTechnology("PHP")
+			s := NewPortfolioService(repo, nil, AssetConfig{})
+
+			_, err := s.ApplyBundle(context.Background(), tt.bundle, models.ImportOptions{Mode: models.ImportReplace})
+			if got := fieldNames(t, err); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("fields = %q, want %q", got, tt.wantFields)
+			}
+			if len(repo.technologies) != 1 || repo.technologies[0].DeletedAt != nil || len(repo.projects) != 0 || len(repo.revisions) != 0 {
+				t.Errorf("a rejected bundle changed the database")
+			}
+		})
+	}
+}
+
+func TestApplyBundleDryRun(t *testing.T) {
+	repo := newFakeRepo()
+	golang := repo.addTechnology("Go")
+	repo.addProject(storedProject("Legacy", golang.ID))
+	s := NewPortfolioService(repo, nil, AssetConfig{})
+
+	bundle := &models.Bundle{
+		Version:      models.BundleVersion,
+		Technologies: []*models.BundleTechnology{{Name: "Rust"}},
+		// Site uses Rust, which only exists within the transaction.
+		Projects: []*models.BundleProject{bundleProject("Site", "Rust")},
+	}
+	plan, err := s.ApplyBundle(context.Background(), bundle, models.ImportOptions{Mode: models.ImportReplace, DryRun: true})
+	if err != nil {
+		t.Fatalf("ApplyBundle() error = %v", err)
+	}
+	want := []string{
+		"create technology Rust",
+		"delete technology Go",
+		"create project Site",
+		"delete project Legacy",
+	}
+	if got := planSummary(plan); !slices.Equal(got, want) {
+		t.Errorf("plan = %q, want %q", got, want)
+	}
+	if plan.Count(models.PlanCreate) != 2 || plan.Count(models.PlanDelete) != 2 {
+		t.Errorf("plan counts = %d created, %d deleted, want 2 and 2", plan.Count(models.PlanCreate), plan.Count(models.PlanDelete))
+	}
+
+	if len(repo.technologies) != 1 || repo.technologies[0].DeletedAt != nil {
+		t.Errorf("technologies = %+v, want Go alone and live", repo.technologies)
+	}
+	if len(repo.projects) != 1 || repo.projects[0].DeletedAt != nil {
+		t.Errorf("projects = %+v, want Legacy alone and live", repo.projects)
+	}
+	if len(repo.revisions) != 0 {
+		t.Errorf("dry run recorded %d revisions", len(repo.revisions))
+	}
+}
+
+func TestApplyBundleRecordsRevisions(t *testing.T) {
+	repo := newFakeRepo()
+	repo.addProject(storedProject("Site"))
+	s := NewPortfolioService(repo, nil, AssetConfig{})
+
+	changed := bundleProject("Site")
+	changed.Description = "New description"
+	bundle := &models.Bundle{Version: models.BundleVersion, Projects: []*models.BundleProject{changed}}
+	plan, err := s.ApplyBundle(context.Background(), bundle, models.ImportOptions{Mode: models.ImportMerge})
+	if err != nil {
+		t.Fatalf("ApplyBundle() error = %v", err)
+	}
+	step := plan.Steps[0]
+	if len(step.Changes) != 1 || step.Changes[0].Field != "description" {
+		t.Errorf("changes = %+v, want the description", step.Changes)
+	}
+	if len(repo.revisions) != 1 || repo.revisions[0].Action != models.RevisionUpdate {
+		t.Fatalf("revisions = %+v, want one update", repo.revisions)
+	}
+	// An empty slug in the bundle keeps the current one.
+	if project := repo.projectByTitle("Site"); project.Slug != "site" || project.Description != "New description" {
+		t.Errorf("project = %+v", project)
+	}
+}
+
+func TestApplyBundleChecksVersionAndMode(t *testing.T) {
+	s := NewPortfolioService(newFakeRepo(), nil, AssetConfig{})
+	ctx := context.Background()
+
+	_, err := s.ApplyBundle(ctx, &models.Bundle{Version: models.BundleVersion + 1}, models.ImportOptions{Mode: models.ImportMerge})
+	if !errors.Is(err, apperrors.ErrBadRequest) {
+		t.Errorf("unsupported version: error = %v, want bad request", err)
+	}
+	_, err = s.ApplyBundle(ctx, &models.Bundle{Version: models.BundleVersion}, models.ImportOptions{Mode: "upsert"})
+	if !errors.Is(err, apperrors.ErrBadRequest) {
+		t.Errorf("invalid mode: error = %v, want bad request", err)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// TestExportImportBundle moves a portfolio to another database through a
+// bundle.
+func TestExportImportBundle(t *testing.T) {
+	ctx := context.Background()
+	source := newFakeRepo()
+	source.nextID = 500
+	golang := source.addTechnology("Go")
+	rust := source.addTechnology("Rust")
+	source.addProject(storedProject("Site", rust.ID, golang.ID))
+
+	bundle, err := NewPortfolioService(source, nil, AssetConfig{}).ExportBundle(ctx)
+	if err != nil {
+		t.Fatalf("ExportBundle() error = %v", err)
+	}
+	if got := bundle.Projects[0].Technologies; !slices.Equal(got, []string{"Rust", "Go"}) {
+		t.Errorf("exported technologies = %q, want names", got)
+	}
+
+	target := newFakeRepo()
+	report, err := NewPortfolioService(target, nil, AssetConfig{}).ImportBundle(ctx, bundle, models.ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportBundle() error = %v", err)
+	}
+	if report.Mode != models.ImportMerge {
+		t.Errorf("mode = %s, want merge by default", report.Mode)
+	}
+	if !slices.Equal(report.Technologies.Created, []string{"Go", "Rust"}) || !slices.Equal(report.Projects.Created, []string{"Site"}) {
+		t.Errorf("report = %+v %+v", report.Technologies, report.Projects)
+	}
+	project := target.projectByTitle("Site")
+	want := []int64{target.technologyByName("Rust").ID, target.technologyByName("Go").ID}
+	if project == nil || !slices.Equal(project.TechnologyIDs, want) {
+		t.Errorf("imported project = %+v, want technology ids %v", project, want)
+	}
+
+	// Importing the same bundle again changes nothing.
+	report, err = NewPortfolioService(target, nil, AssetConfig{}).ImportBundle(ctx, bundle, models.ImportOptions{})
+	if err != nil {
+		t.Fatalf("second ImportBundle() error = %v", err)
+	}
+	if len(report.Technologies.Unchanged) != 2 || len(report.Projects.Unchanged) != 1 {
+		t.Errorf("second import report = %+v %+v, want everything unchanged", report.Technologies, report.Projects)
+	}
+}
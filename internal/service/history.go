@@ -0,0 +1,215 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/internal/validation"
+	"slices"
+)
+
+func projectSnapshot(project *models.Project) json.RawMessage {
+	if project == nil {
+		return nil
+	}
+	data, _ := json.Marshal(models.NewProjectSnapshot(project))
+	return data
+}
+
+func technologySnapshot(technology *models.Technology) json.RawMessage {
+	if technology == nil {
+		return nil
+	}
+	data, _ := json.Marshal(models.NewTechnologySnapshot(technology))
+	return data
+}
+
+func (s *PortfolioService) recordProject(ctx context.Context, action models.RevisionAction, id int64, before, after *models.Project) error {
+	return s.recordRevision(ctx, models.RevisionProject, id, action, projectSnapshot(before), projectSnapshot(after))
+}
+
+func (s *PortfolioService) recordTechnology(ctx context.Context, action models.RevisionAction, id int64, before, after *models.Technology) error {
+	return s.recordRevision(ctx, models.RevisionTechnology, id, action, technologySnapshot(before), technologySnapshot(after))
+}
+
+// recordRevision saves a change made by the caller in ctx. before is nil
+// when the record didn't exist before the change, after when it is gone
+// after it. Changes that leave the snapshot as it was are not saved.
+func (s *PortfolioService) recordRevision(ctx context.Context, entity models.RevisionEntity, id int64, action models.RevisionAction, before, after json.RawMessage) error {
+	if before != nil && after != nil && bytes.Equal(before, after) {
+		return nil
+	}
+	revision := &models.Revision{Entity: entity, EntityID: id, Action: action, Before: before, After: after}
+	if principal := models.PrincipalFromContext(ctx); principal != nil {
+		if principal.UserID != 0 {
+			revision.Actor.UserID = &principal.UserID
+		}
+		if principal.APIKeyID != 0 {
+			revision.Actor.APIKeyID = &principal.APIKeyID
+		}
+	}
+	return s.portfolioRepo.CreateRevision(ctx, revision)
+}
+
+// ProjectHistory returns the revisions of a project, newest first, with
+// the fields each of them changed.
+func (s *PortfolioService) ProjectHistory(ctx context.Context, id int64) ([]*models.Revision, error) {
+	revisions, err := s.portfolioRepo.ListRevisions(ctx, models.RevisionProject, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		// Tell a project without history apart from a missing one.
+		if _, err := s.portfolioRepo.GetProject(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	setChanges(revisions)
+	return revisions, nil
+}
+
+func (s *PortfolioService) TechnologyHistory(ctx context.Context, id int64) ([]*models.Revision, error) {
+	revisions, err := s.portfolioRepo.ListRevisions(ctx, models.RevisionTechnology, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		if _, err := s.portfolioRepo.GetTechnology(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	setChanges(revisions)
+	return revisions, nil
+}
+
+// RevertProject brings a project back to the state it had after the
+// revision. The revert is recorded as a revision of its own.
+func (s *PortfolioService) RevertProject(ctx context.Context, id, revisionID int64) (*models.Project, error) {
+	var project *models.Project
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		revision, err := s.portfolioRepo.GetRevision(ctx, models.RevisionProject, id, revisionID)
+		if err != nil {
+			return err
+		}
+		if revision.After == nil {
+			return apperrors.Conflict("revision_without_state", "Revision %d deleted project %d, revert to an earlier revision", revisionID, id)
+		}
+		var snapshot models.ProjectSnapshot
+		if err := json.Unmarshal(revision.After, &snapshot); err != nil {
+			return fmt.Errorf("service.RevertProject: %w", err)
+		}
+
+		current, err := s.portfolioRepo.GetProject(ctx, id)
+		if err != nil {
+			return err
+		}
+		target := snapshot.Project(id)
+		if err := s.validateProject(ctx, target); err != nil {
+			return err
+		}
+		if err := s.portfolioRepo.UpdateProject(ctx, target); err != nil {
+			return err
+		}
+		if project, err = s.portfolioRepo.GetProject(ctx, id); err != nil {
+			return err
+		}
+		return s.recordProject(ctx, models.RevisionRevert, id, current, project)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.setAssetURLs(project)
+	return project, nil
+}
+
+func (s *PortfolioService) RevertTechnology(ctx context.Context, id, revisionID int64) (*models.Technology, error) {
+	var technology *models.Technology
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		revision, err := s.portfolioRepo.GetRevision(ctx, models.RevisionTechnology, id, revisionID)
+		if err != nil {
+			return err
+		}
+		if revision.After == nil {
+			return apperrors.Conflict("revision_without_state", "Revision %d deleted technology %d, revert to an earlier revision", revisionID, id)
+		}
+		var snapshot models.TechnologySnapshot
+		if err := json.Unmarshal(revision.After, &snapshot); err != nil {
+			return fmt.Errorf("service.RevertTechnology: %w", err)
+		}
+
+		current, err := s.portfolioRepo.GetTechnology(ctx, id)
+		if err != nil {
+			return err
+		}
+		target := snapshot.Technology(id)
+		if err := validation.Struct(target); err != nil {
+			return err
+		}
+		if err := sanitizeIcon(target); err != nil {
+			return err
+		}
+		if err := s.portfolioRepo.UpdateTechnology(ctx, target); err != nil {
+			return err
+		}
+		technology, err = s.portfolioRepo.GetTechnology(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.recordTechnology(ctx, models.RevisionRevert, id, current, technology)
+	})
+	return technology, err
+}
+
+func setChanges(revisions []*models.Revision) {
+	for _, revision := range revisions {
+		revision.Changes = diffSnapshots(revision.Before, revision.After)
+	}
+}
+
+// diffSnapshots lists the top-level fields whose values differ between two
+// snapshots, in field name order. A missing snapshot has null fields.
+func diffSnapshots(before, after json.RawMessage) []*models.FieldChange {
+	beforeFields, afterFields := snapshotFields(before), snapshotFields(after)
+
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	changes := []*models.FieldChange{}
+	for _, name := range names {
+		b, a := beforeFields[name], afterFields[name]
+		if !bytes.Equal(compactJSON(b), compactJSON(a)) {
+			changes = append(changes, &models.FieldChange{Field: name, Before: b, After: a})
+		}
+	}
+	return changes
+}
+
+func snapshotFields(snapshot json.RawMessage) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if snapshot != nil {
+		_ = json.Unmarshal(snapshot, &fields)
+	}
+	return fields
+}
+
+func compactJSON(data json.RawMessage) []byte {
+	if data == nil {
+		return []byte("null")
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
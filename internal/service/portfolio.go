@@ -2,67 +2,377 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"gowebsite/internal/apperrors"
 	"gowebsite/internal/models"
+	"gowebsite/internal/validation"
+	"gowebsite/pkg/svg"
+	"slices"
+	"strings"
 )
 
 type OrderRepo interface {
 	CreateTechnology(ctx context.Context, technology *models.Technology) (int64, error)
 	GetTechnology(ctx context.Context, id int64) (*models.Technology, error)
-	ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, error)
+	ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, *models.PageInfo, error)
+	ExistingTechnologyIDs(ctx context.Context, ids []int64) ([]int64, error)
+	TechnologyStats(ctx context.Context, top uint64) ([]*models.TechnologyStats, error)
+	CreateAsset(ctx context.Context, asset *models.ProjectAsset) (*models.ProjectAsset, error)
+	ListProjectAssets(ctx context.Context, projectID int64, forUpdate bool) ([]*models.ProjectAsset, error)
+	GetAsset(ctx context.Context, projectID, id int64) (*models.ProjectAsset, error)
+	UpdateAssetCaption(ctx context.Context, projectID, id int64, caption string) error
+	SetAssetPositions(ctx context.Context, projectID int64, ids []int64) error
+	DeleteAsset(ctx context.Context, projectID, id int64) error
 	DeleteTechnology(ctx context.Context, id int64) error
+	RestoreTechnology(ctx context.Context, id int64) error
 	PatchTechnology(ctx context.Context, technology *models.Technology) error
 	CreateProject(ctx context.Context, project *models.Project) (int64, error)
 	GetProject(ctx context.Context, id int64) (*models.Project, error)
-	ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, error)
+	GetProjectForUpdate(ctx context.Context, id int64) (*models.Project, error)
+	GetProjectBySlug(ctx context.Context, slug string) (*models.Project, error)
+	ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, *models.PageInfo, error)
 	DeleteProject(ctx context.Context, id int64) error
+	RestoreProject(ctx context.Context, id int64) error
 	PatchProject(ctx context.Context, project *models.Project, projectUpdate *models.Project) error
+	UpdateTechnology(ctx context.Context, technology *models.Technology) error
+	UpdateProject(ctx context.Context, project *models.Project) error
+	CreateRevision(ctx context.Context, revision *models.Revision) error
+	ListRevisions(ctx context.Context, entity models.RevisionEntity, entityID int64) ([]*models.Revision, error)
+	GetRevision(ctx context.Context, entity models.RevisionEntity, entityID, id int64) (*models.Revision, error)
+	CreateRelease(ctx context.Context, release *models.Release) (*models.Release, error)
+	GetRelease(ctx context.Context, projectID, id int64) (*models.Release, error)
+	ListReleases(ctx context.Context, projectID int64, filter *models.ReleaseFilter) ([]*models.Release, *models.PageInfo, error)
+	ListRecentReleases(ctx context.Context, filter *models.ProjectFilter) ([]*models.ProjectRelease, error)
+	PatchRelease(ctx context.Context, projectID, id int64, update *models.ReleaseUpdate) error
+	DeleteRelease(ctx context.Context, projectID, id int64) error
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type PortfolioService struct {
 	portfolioRepo OrderRepo
+	storage       FileStorage
+	assetCfg      AssetConfig
 }
 
-func NewPortfolioService(repo OrderRepo) *PortfolioService {
-	return &PortfolioService{portfolioRepo: repo}
+func NewPortfolioService(repo OrderRepo, storage FileStorage, assetCfg AssetConfig) *PortfolioService {
+	return &PortfolioService{portfolioRepo: repo, storage: storage, assetCfg: assetCfg}
 }
 
 func (s *PortfolioService) CreateTechnology(ctx context.Context, technology *models.Technology) (int64, error) {
-	return s.portfolioRepo.CreateTechnology(ctx, technology)
+	if err := validation.Struct(technology); err != nil {
+		return 0, err
+	}
+	if err := sanitizeIcon(technology); err != nil {
+		return 0, err
+	}
+	var technologyID int64
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		technologyID, err = s.portfolioRepo.CreateTechnology(ctx, technology)
+		if err != nil {
+			return err
+		}
+		return s.recordTechnology(ctx, models.RevisionCreate, technologyID, nil, technology)
+	})
+	return technologyID, err
 }
 
 func (s *PortfolioService) GetTechnology(ctx context.Context, id int64) (*models.Technology, error) {
 	return s.portfolioRepo.GetTechnology(ctx, id)
 }
 
-func (s *PortfolioService) ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, error) {
+func (s *PortfolioService) ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, *models.PageInfo, error) {
 	return s.portfolioRepo.ListTechnologies(ctx, filter)
 }
 
+// TechnologyIcon returns the sanitized SVG icon of a technology. Icons
+// stored before sanitization was introduced are cleaned up on the way out.
+func (s *PortfolioService) TechnologyIcon(ctx context.Context, id int64) ([]byte, error) {
+	technology, err := s.portfolioRepo.GetTechnology(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !technology.Svg.Valid || technology.Svg.String == "" {
+		return nil, apperrors.NotFound("icon_not_found", "Technology with id %d has no icon", id)
+	}
+	icon, err := svg.Sanitize(technology.Svg.String)
+	if err != nil {
+		return nil, apperrors.NotFound("icon_not_found", "Technology with id %d has no valid icon", id)
+	}
+	return []byte(icon), nil
+}
+
+// sanitizeIcon replaces the SVG of technology with its sanitized version.
+func sanitizeIcon(technology *models.Technology) error {
+	if !technology.Svg.Valid || technology.Svg.String == "" {
+		return nil
+	}
+	icon, err := svg.Sanitize(technology.Svg.String)
+	if err != nil {
+		return apperrors.InvalidFields([]apperrors.FieldError{{Field: "svg", Code: "svg", Message: err.Error()}})
+	}
+	technology.Svg.String = icon
+	return nil
+}
+
+func (s *PortfolioService) TechnologyStats(ctx context.Context, filter *models.TechnologyStatsFilter) ([]*models.TechnologyStats, error) {
+	top := filter.Top
+	if top == 0 {
+		top = models.DefaultStatsTop
+	}
+	if top > models.MaxStatsTop {
+		return nil, apperrors.BadRequest("invalid_top", "top must be at most %d", models.MaxStatsTop)
+	}
+	return s.portfolioRepo.TechnologyStats(ctx, top)
+}
+
 func (s *PortfolioService) DeleteTechnology(ctx context.Context, id int64) error {
-	return s.portfolioRepo.DeleteTechnology(ctx, id)
+	return s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		current, err := s.portfolioRepo.GetTechnology(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.portfolioRepo.DeleteTechnology(ctx, id); err != nil {
+			return err
+		}
+		return s.recordTechnology(ctx, models.RevisionDelete, id, current, nil)
+	})
+}
+
+func (s *PortfolioService) RestoreTechnology(ctx context.Context, id int64) (*models.Technology, error) {
+	var technology *models.Technology
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.portfolioRepo.RestoreTechnology(ctx, id); err != nil {
+			return err
+		}
+		var err error
+		technology, err = s.portfolioRepo.GetTechnology(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.recordTechnology(ctx, models.RevisionRestore, id, nil, technology)
+	})
+	return technology, err
 }
 
 func (s *PortfolioService) PatchTechnology(ctx context.Context, technology *models.Technology) error {
-	return s.portfolioRepo.PatchTechnology(ctx, technology)
+	return s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		current, err := s.portfolioRepo.GetTechnology(ctx, technology.ID)
+		if err != nil {
+			return err
+		}
+		if err := validation.Struct(mergeTechnology(current, technology)); err != nil {
+			if err := onlyPatched(err, technology); err != nil {
+				return err
+			}
+		}
+		if err := sanitizeIcon(technology); err != nil {
+			return err
+		}
+		if err := s.portfolioRepo.PatchTechnology(ctx, technology); err != nil {
+			return err
+		}
+		after, err := s.portfolioRepo.GetTechnology(ctx, technology.ID)
+		if err != nil {
+			return err
+		}
+		return s.recordTechnology(ctx, models.RevisionUpdate, technology.ID, current, after)
+	})
 }
 
 func (s *PortfolioService) CreateProject(ctx context.Context, project *models.Project) (int64, error) {
-
-	return s.portfolioRepo.CreateProject(ctx, project)
+	var projectID int64
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.validateProject(ctx, project); err != nil {
+			return err
+		}
+		var err error
+		projectID, err = s.portfolioRepo.CreateProject(ctx, project)
+		if err != nil {
+			return err
+		}
+		// The stored project is recorded: the repository derives the slug
+		// and the version from the releases.
+		created, err := s.portfolioRepo.GetProject(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		return s.recordProject(ctx, models.RevisionCreate, projectID, nil, created)
+	})
+	return projectID, err
 }
 
 func (s *PortfolioService) GetProject(ctx context.Context, id int64) (*models.Project, error) {
-	return s.portfolioRepo.GetProject(ctx, id)
+	project, err := s.portfolioRepo.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.setAssetURLs(project)
+	return project, nil
+}
+
+func (s *PortfolioService) GetProjectBySlug(ctx context.Context, slug string) (*models.Project, error) {
+	project, err := s.portfolioRepo.GetProjectBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	s.setAssetURLs(project)
+	return project, nil
 }
 
-func (s *PortfolioService) ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, error) {
-	return s.portfolioRepo.ListProjects(ctx, filter)
+func (s *PortfolioService) ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, *models.PageInfo, error) {
+	projects, info, err := s.portfolioRepo.ListProjects(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.setAssetURLs(projects...)
+	return projects, info, nil
 }
 
+// DeleteProject moves the project to the trash. Its assets and their files
+// are kept until it is restored.
 func (s *PortfolioService) DeleteProject(ctx context.Context, id int64) error {
-	return s.portfolioRepo.DeleteProject(ctx, id)
+	return s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		current, err := s.portfolioRepo.GetProject(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.portfolioRepo.DeleteProject(ctx, id); err != nil {
+			return err
+		}
+		return s.recordProject(ctx, models.RevisionDelete, id, current, nil)
+	})
+}
+
+func (s *PortfolioService) RestoreProject(ctx context.Context, id int64) (*models.Project, error) {
+	var project *models.Project
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.portfolioRepo.RestoreProject(ctx, id); err != nil {
+			return err
+		}
+		var err error
+		project, err = s.portfolioRepo.GetProject(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.recordProject(ctx, models.RevisionRestore, id, nil, project)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.setAssetURLs(project)
+	return project, nil
+}
+
+// PatchProject applies the fields set in projectUpdate to the project. The
+// project is read and locked in the same transaction as the patch, so the
+// checks and the recorded revision see the state the patch applies to.
+func (s *PortfolioService) PatchProject(ctx context.Context, id int64, projectUpdate *models.Project) error {
+	return s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		project, err := s.portfolioRepo.GetProjectForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.validateProject(ctx, mergeProject(project, projectUpdate)); err != nil {
+			if err := onlyPatched(err, projectUpdate); err != nil {
+				return err
+			}
+		}
+		if err := s.portfolioRepo.PatchProject(ctx, project, projectUpdate); err != nil {
+			return err
+		}
+		// The stored project is recorded rather than the merged one: a
+		// patched version only becomes the version if it is the latest.
+		after, err := s.portfolioRepo.GetProject(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.recordProject(ctx, models.RevisionUpdate, id, project, after)
+	})
+}
+
+// validateProject reports the broken validation rules of project together
+// with the technologies it references that don't exist.
+func (s *PortfolioService) validateProject(ctx context.Context, project *models.Project) error {
+	fields := validation.Check(project)
+
+	existing, err := s.portfolioRepo.ExistingTechnologyIDs(ctx, project.TechnologyIDs)
+	if err != nil {
+		return err
+	}
+	for i, id := range project.TechnologyIDs {
+		if id > 0 && !slices.Contains(existing, id) {
+			fields = append(fields, apperrors.FieldError{
+				Field:   fmt.Sprintf("tech_id[%d]", i),
+				Code:    "not_found",
+				Message: fmt.Sprintf("technology %d does not exist", id),
+			})
+		}
+	}
+	return apperrors.InvalidFields(fields)
+}
+
+// onlyPatched drops the field errors of fields the patch does not touch, so
+// rows stored before a rule was introduced can still be patched. Errors of
+// rules spanning several fields are kept. It returns nil when no error is
+// left, in which case the patch goes ahead.
+func onlyPatched(err error, update any) error {
+	appErr, ok := apperrors.As(err)
+	if !ok || appErr.Fields == nil {
+		return err
+	}
+	patched := validation.SetFields(update)
+	var fields []apperrors.FieldError
+	for _, field := range appErr.Fields {
+		name, _, _ := strings.Cut(field.Field, "[")
+		if patched[name] || field.Code == validation.CodeExclusive {
+			fields = append(fields, field)
+		}
+	}
+	return apperrors.InvalidFields(fields)
+}
+
+// mergeProject returns project with the fields set in update applied, the
+// same way the repository applies a patch.
+func mergeProject(project, update *models.Project) *models.Project {
+	merged := *project
+	if update.Title != "" {
+		merged.Title = update.Title
+	}
+	if update.Slug != "" {
+		merged.Slug = update.Slug
+	}
+	if update.Version != "" {
+		merged.Version = update.Version
+	}
+	if update.Description != "" {
+		merged.Description = update.Description
+	}
+	if update.IsActive.Valid {
+		merged.IsActive = update.IsActive
+	}
+	if update.IsArchived.Valid {
+		merged.IsArchived = update.IsArchived
+	}
+	if update.IsDeveloping.Valid {
+		merged.IsDeveloping = update.IsDeveloping
+	}
+	if update.Links != nil {
+		merged.Links = update.Links
+	}
+	if update.TechnologyIDs != nil {
+		merged.TechnologyIDs = update.TechnologyIDs
+	}
+	return &merged
 }
 
-func (s *PortfolioService) PatchProject(ctx context.Context, project *models.Project, projectUpdate *models.Project) error {
-	return s.portfolioRepo.PatchProject(ctx, project, projectUpdate)
+func mergeTechnology(technology, update *models.Technology) *models.Technology {
+	merged := *technology
+	if update.Name != "" {
+		merged.Name = update.Name
+	}
+	if update.Svg.Valid {
+		merged.Svg = update.Svg
+	}
+	return &merged
 }
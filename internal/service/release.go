@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/internal/validation"
+)
+
+func (s *PortfolioService) ListReleases(ctx context.Context, projectID int64, filter *models.ReleaseFilter) ([]*models.Release, *models.PageInfo, error) {
+	bounds := []struct{ name, version string }{{"min_version", filter.MinVersion}, {"max_version", filter.MaxVersion}}
+	for _, bound := range bounds {
+		if bound.version != "" && !validation.IsSemver(bound.version) {
+			return nil, nil, apperrors.BadRequest("invalid_version", "%s must be a semantic version, got %q", bound.name, bound.version)
+		}
+	}
+	if _, err := s.portfolioRepo.GetProject(ctx, projectID); err != nil {
+		return nil, nil, err
+	}
+	return s.portfolioRepo.ListReleases(ctx, projectID, filter)
+}
+
+// ListRecentReleases lists the releases of the live projects matching the
+// technology filters of filter, the latest released first.
+func (s *PortfolioService) ListRecentReleases(ctx context.Context, filter *models.ProjectFilter) ([]*models.ProjectRelease, error) {
+	return s.portfolioRepo.ListRecentReleases(ctx, filter)
+}
+
+func (s *PortfolioService) GetRelease(ctx context.Context, projectID, id int64) (*models.Release, error) {
+	if _, err := s.portfolioRepo.GetProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return s.portfolioRepo.GetRelease(ctx, projectID, id)
+}
+
+// CreateRelease adds a release to the project, which becomes the version
+// of the project when it is the latest.
+func (s *PortfolioService) CreateRelease(ctx context.Context, projectID int64, release *models.Release) (*models.Release, error) {
+	if err := validation.Struct(release); err != nil {
+		return nil, err
+	}
+	release.ProjectID = projectID
+
+	var created *models.Release
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		project, err := s.portfolioRepo.GetProject(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		if created, err = s.portfolioRepo.CreateRelease(ctx, release); err != nil {
+			return err
+		}
+		return s.recordVersionChange(ctx, project)
+	})
+	return created, err
+}
+
+func (s *PortfolioService) PatchRelease(ctx context.Context, projectID, id int64, update *models.ReleaseUpdate) (*models.Release, error) {
+	if err := validation.Struct(update); err != nil {
+		return nil, err
+	}
+
+	var release *models.Release
+	err := s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		project, err := s.portfolioRepo.GetProject(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		if err := s.portfolioRepo.PatchRelease(ctx, projectID, id, update); err != nil {
+			return err
+		}
+		if err := s.recordVersionChange(ctx, project); err != nil {
+			return err
+		}
+		release, err = s.portfolioRepo.GetRelease(ctx, projectID, id)
+		return err
+	})
+	return release, err
+}
+
+func (s *PortfolioService) DeleteRelease(ctx context.Context, projectID, id int64) error {
+	return s.portfolioRepo.WithTx(ctx, func(ctx context.Context) error {
+		project, err := s.portfolioRepo.GetProject(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		if err := s.portfolioRepo.DeleteRelease(ctx, projectID, id); err != nil {
+			return err
+		}
+		return s.recordVersionChange(ctx, project)
+	})
+}
+
+// recordVersionChange records the update of a project whose version a
+// release change may have moved; nothing is recorded when it didn't.
+func (s *PortfolioService) recordVersionChange(ctx context.Context, before *models.Project) error {
+	after, err := s.portfolioRepo.GetProject(ctx, before.ID)
+	if err != nil {
+		return err
+	}
+	return s.recordProject(ctx, models.RevisionUpdate, before.ID, before, after)
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"slices"
+	"time"
+)
+
+// fakeRepo keeps technologies, projects, assets and revisions in memory. WithTx
+// restores the state when its function fails, like a rolled back
+// transaction. Methods the tests don't need panic through the nil
+// embedded interface.
+type fakeRepo struct {
+	OrderRepo
+	technologies []*models.Technology
+	projects     []*models.Project
+	assets       []*models.ProjectAsset
+	revisions    []*models.Revision
+	nextID       int64
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{nextID: 100}
+}
+
+func (r *fakeRepo) id() int64 {
+	r.nextID++
+	return r.nextID
+}
+
+func (r *fakeRepo) addTechnology(name string) *models.Technology {
+	technology := &models.Technology{ID: r.id(), Name: name}
+	r.technologies = append(r.technologies, technology)
+	return technology
+}
+
+func (r *fakeRepo) addProject(project *models.Project) *models.Project {
+	project.ID = r.id()
+	if project.Slug == "" {
+		project.Slug = models.Slugify(project.Title)
+	}
+	r.projects = append(r.projects, project)
+	return project
+}
+
+func (r *fakeRepo) technologyByName(name string) *models.Technology {
+	for _, technology := range r.technologies {
+		if technology.Name == name && technology.DeletedAt == nil {
+			return technology
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) projectByTitle(title string) *models.Project {
+	for _, project := range r.projects {
+		if project.Title == title && project.DeletedAt == nil {
+			return project
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	technologies := make([]*models.Technology, len(r.technologies))
+	for i, technology := range r.technologies {
+		copied := *technology
+		technologies[i] = &copied
+	}
+	projects := make([]*models.Project, len(r.projects))
+	for i, project := range r.projects {
+		copied := *project
+		projects[i] = &copied
+	}
+	assets, revisions, nextID := slices.Clone(r.assets), slices.Clone(r.revisions), r.nextID
+	if err := fn(ctx); err != nil {
+		r.technologies, r.projects, r.assets, r.revisions, r.nextID = technologies, projects, assets, revisions, nextID
+		return err
+	}
+	return nil
+}
+
+func (r *fakeRepo) ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, *models.PageInfo, error) {
+	result := []*models.Technology{}
+	for _, technology := range r.technologies {
+		if technology.DeletedAt == nil {
+			copied := *technology
+			result = append(result, &copied)
+		}
+	}
+	return result, &models.PageInfo{Total: int64(len(result))}, nil
+}
+
+func (r *fakeRepo) GetTechnology(ctx context.Context, id int64) (*models.Technology, error) {
+	for _, technology := range r.technologies {
+		if technology.ID == id && technology.DeletedAt == nil {
+			copied := *technology
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.NotFound("technology_not_found", "Technology with id %d not found", id)
+}
+
+func (r *fakeRepo) ExistingTechnologyIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	result := []int64{}
+	for _, technology := range r.technologies {
+		if technology.DeletedAt == nil && slices.Contains(ids, technology.ID) {
+			result = append(result, technology.ID)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeRepo) CreateTechnology(ctx context.Context, technology *models.Technology) (int64, error) {
+	copied := *technology
+	copied.ID = r.id()
+	r.technologies = append(r.technologies, &copied)
+	return copied.ID, nil
+}
+
+func (r *fakeRepo) UpdateTechnology(ctx context.Context, technology *models.Technology) error {
+	for _, stored := range r.technologies {
+		if stored.ID == technology.ID && stored.DeletedAt == nil {
+			stored.Name, stored.Svg = technology.Name, technology.Svg
+			return nil
+		}
+	}
+	return apperrors.NotFound("technology_not_found", "Technology with id %d not found", technology.ID)
+}
+
+func (r *fakeRepo) DeleteTechnology(ctx context.Context, id int64) error {
+	for _, stored := range r.technologies {
+		if stored.ID == id && stored.DeletedAt == nil {
+			now := time.Now()
+			stored.DeletedAt = &now
+			return nil
+		}
+	}
+	return apperrors.NotFound("technology_not_found", "Technology with id %d not found", id)
+}
+
+func (r *fakeRepo) ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, *models.PageInfo, error) {
+	result := []*models.Project{}
+	for _, project := range r.projects {
+		if project.DeletedAt == nil {
+			copied := *project
+			result = append(result, &copied)
+		}
+	}
+	return result, &models.PageInfo{Total: int64(len(result))}, nil
+}
+
+func (r *fakeRepo) GetProject(ctx context.Context, id int64) (*models.Project, error) {
+	for _, project := range r.projects {
+		if project.ID == id && project.DeletedAt == nil {
+			copied := *project
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.NotFound("project_not_found", "Project with id %d not found", id)
+}
+
+func (r *fakeRepo) CreateProject(ctx context.Context, project *models.Project) (int64, error) {
+	copied := *project
+	copied.TechnologyIDs = slices.Clone(project.TechnologyIDs)
+	return r.addProject(&copied).ID, nil
+}
+
+func (r *fakeRepo) UpdateProject(ctx context.Context, project *models.Project) error {
+	for i, stored := range r.projects {
+		if stored.ID == project.ID && stored.DeletedAt == nil {
+			copied := *project
+			copied.TechnologyIDs = slices.Clone(project.TechnologyIDs)
+			if copied.Slug == "" {
+				copied.Slug = stored.Slug
+			}
+			r.projects[i] = &copied
+			return nil
+		}
+	}
+	return apperrors.NotFound("project_not_found", "Project with id %d not found", project.ID)
+}
+
+func (r *fakeRepo) DeleteProject(ctx context.Context, id int64) error {
+	for _, stored := range r.projects {
+		if stored.ID == id && stored.DeletedAt == nil {
+			now := time.Now()
+			stored.DeletedAt = &now
+			return nil
+		}
+	}
+	return apperrors.NotFound("project_not_found", "Project with id %d not found", id)
+}
+
+func (r *fakeRepo) ListProjectAssets(ctx context.Context, projectID int64, forUpdate bool) ([]*models.ProjectAsset, error) {
+	result := []*models.ProjectAsset{}
+	for _, asset := range r.assets {
+		if asset.ProjectID == projectID {
+			copied := *asset
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeRepo) CreateAsset(ctx context.Context, asset *models.ProjectAsset) (*models.ProjectAsset, error) {
+	copied := *asset
+	copied.ID = r.id()
+	r.assets = append(r.assets, &copied)
+	created := copied
+	return &created, nil
+}
+
+func (r *fakeRepo) CreateRevision(ctx context.Context, revision *models.Revision) error {
+	revision.ID = r.id()
+	r.revisions = append(r.revisions, revision)
+	return nil
+}
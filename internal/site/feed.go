@@ -0,0 +1,127 @@
+package site
+
+import (
+	"fmt"
+	"gowebsite/internal/models"
+	"gowebsite/pkg/feed"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FeedSize is the number of entries a feed lists.
+const FeedSize = 50
+
+// FeedFormats are the formats of the feeds, which are named after them:
+// feeds/projects.atom, feeds/projects.rss and feeds/projects.json.
+var FeedFormats = []feed.Format{feed.Atom, feed.RSS, feed.JSON}
+
+// FeedPath is the path of the project feed in format, relative to the
+// home page.
+func FeedPath(format feed.Format) string {
+	return "feeds/projects." + string(format)
+}
+
+// ProjectFeed lists projects and their releases by their last update, the
+// latest first and at most FeedSize of them. A project shows up when it is
+// created and moves up whenever it changes; every release is an entry of
+// its own, published at its release date. technologies are the ones the
+// projects and releases were filtered by, none for all of them. Links are
+// made absolute with cfg.URL; the caller sets the FeedURL of the format it
+// writes.
+//
+// Entries are identified by tag URIs built from the host of cfg.URL and
+// the ids and creation dates of the rows, which survive title, slug and
+// version changes.
+func ProjectFeed(cfg SiteConfig, dirLinks bool, technologies []*models.Technology, projects []*models.Project, releases []*models.ProjectRelease) (*feed.Feed, error) {
+	home, err := url.Parse(cfg.URL)
+	if err != nil || !home.IsAbs() || home.Hostname() == "" {
+		return nil, fmt.Errorf("site URL %q is not an absolute URL", cfg.URL)
+	}
+	if !strings.HasSuffix(home.Path, "/") {
+		home.Path += "/"
+	}
+	link := func(slug string) string {
+		path := "projects/" + slug
+		if dirLinks {
+			path += "/"
+		}
+		return home.JoinPath(path).String()
+	}
+	tag := func(created time.Time, specific string) string {
+		return fmt.Sprintf("tag:%s,%s:%s", home.Hostname(), created.UTC().Format(time.DateOnly), specific)
+	}
+
+	title := cfg.Title + ": projects"
+	id := home.JoinPath("feeds/projects").String()
+	if len(technologies) > 0 {
+		names := make([]string, len(technologies))
+		ids := make([]string, len(technologies))
+		for i, technology := range technologies {
+			names[i] = technology.Name
+			ids[i] = "tech_id=" + strconv.FormatInt(technology.ID, 10)
+		}
+		slices.Sort(ids)
+		title = cfg.Title + ": " + strings.Join(names, ", ") + " projects"
+		id += "?" + strings.Join(ids, "&")
+	}
+
+	entries := make([]*feed.Entry, 0, len(projects)+len(releases))
+	for _, project := range projects {
+		categories := make([]string, len(project.Technologies))
+		for i, technology := range project.Technologies {
+			categories[i] = technology.Name
+		}
+		entries = append(entries, &feed.Entry{
+			ID:         tag(project.CreatedAt, "projects/"+strconv.FormatInt(project.ID, 10)),
+			Title:      project.Title,
+			Link:       link(project.Slug),
+			Summary:    project.Description,
+			Published:  project.CreatedAt,
+			Updated:    project.UpdatedAt,
+			Categories: categories,
+		})
+	}
+	for _, release := range releases {
+		updated := release.UpdatedAt
+		if release.ReleasedAt.After(updated) {
+			updated = release.ReleasedAt
+		}
+		entries = append(entries, &feed.Entry{
+			ID:         tag(release.CreatedAt, fmt.Sprintf("projects/%d/releases/%d", release.ProjectID, release.ID)),
+			Title:      release.ProjectTitle + " " + release.Version,
+			Link:       link(release.ProjectSlug),
+			Summary:    release.Notes,
+			Published:  release.ReleasedAt,
+			Updated:    updated,
+			Categories: release.Technologies,
+		})
+	}
+	slices.SortStableFunc(entries, func(a, b *feed.Entry) int {
+		if c := b.Updated.Compare(a.Updated); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	if len(entries) > FeedSize {
+		entries = entries[:FeedSize]
+	}
+
+	result := &feed.Feed{
+		ID:          id,
+		Title:       title,
+		Description: "New and updated projects of " + cfg.Title + " and their releases",
+		Author:      cfg.Title,
+		Link:        home.String(),
+		// An empty feed has not changed since the epoch rather than now,
+		// so it keeps its ETag.
+		Updated: time.Unix(0, 0).UTC(),
+		Entries: entries,
+	}
+	if len(entries) > 0 {
+		result.Updated = entries[0].Updated
+	}
+	return result, nil
+}
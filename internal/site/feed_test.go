@@ -0,0 +1,168 @@
+package site
+
+import (
+	"fmt"
+	"gowebsite/internal/models"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var feedConfig = SiteConfig{Title: "Portfolio", URL: "https://example.com/portfolio"}
+
+func feedProject(id int64, title string, created, updated time.Time) *models.Project {
+	return &models.Project{
+		ID:        id,
+		Title:     title,
+		Slug:      models.Slugify(title),
+		Version:   "1.0.0",
+		CreatedAt: created,
+		UpdatedAt: updated,
+	}
+}
+
+func feedRelease(id int64, project *models.Project, version string, released time.Time) *models.ProjectRelease {
+	return &models.ProjectRelease{
+		Release: models.Release{
+			ID:         id,
+			ProjectID:  project.ID,
+			Version:    version,
+			ReleasedAt: released,
+			CreatedAt:  released,
+			UpdatedAt:  released,
+		},
+		ProjectTitle: project.Title,
+		ProjectSlug:  project.Slug,
+	}
+}
+
+func entryIDs(t *testing.T, projects []*models.Project, releases []*models.ProjectRelease) []string {
+	t.Helper()
+	f, err := ProjectFeed(feedConfig, false, nil, projects, releases)
+	if err != nil {
+		t.Fatalf("ProjectFeed() error = %v", err)
+	}
+	ids := make([]string, len(f.Entries))
+	for i, entry := range f.Entries {
+		ids[i] = entry.ID
+	}
+	return ids
+}
+
+func TestProjectFeedStableIDs(t *testing.T) {
+	created := time.Date(2024, 5, 6, 23, 30, 0, 0, time.FixedZone("", -3*3600))
+	project := feedProject(7, "Site", created, created)
+	release := feedRelease(9, project, "1.0.0", created.Add(time.Hour))
+	before := entryIDs(t, []*models.Project{project}, []*models.ProjectRelease{release})
+	want := []string{
+		"tag:example.com,2024-05-07:projects/7/releases/9",
+		"tag:example.com,2024-05-07:projects/7",
+	}
+	if !slices.Equal(before, want) {
+		t.Fatalf("entry ids = %q, want %q", before, want)
+	}
+
+	// Renaming the project and correcting the release keep the ids.
+	project.Title, project.Slug, project.Version = "Portfolio site", "portfolio-site", "2.0.0"
+	project.UpdatedAt = created.Add(2 * time.Hour)
+	release.ProjectTitle, release.ProjectSlug, release.Version = project.Title, project.Slug, "1.0.1"
+	release.UpdatedAt = created.Add(3 * time.Hour)
+	after := entryIDs(t, []*models.Project{project}, []*models.ProjectRelease{release})
+	if !slices.Equal(after, want) {
+		t.Errorf("entry ids after the changes = %q, want %q", after, want)
+	}
+}
+
+func TestProjectFeedOrder(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := feedProject(1, "Old", base, base)
+	edited := feedProject(2, "Edited", base, base.Add(5*time.Hour))
+	released := feedRelease(3, old, "1.0.0", base.Add(time.Hour))
+	// A release edited after the fact moves up with its update.
+	corrected := feedRelease(4, old, "0.9.0", base.Add(-time.Hour))
+	corrected.UpdatedAt = base.Add(3 * time.Hour)
+	// A release dated in the future is ordered by its release date.
+	scheduled := feedRelease(5, edited, "2.0.0", base.Add(4*time.Hour))
+	scheduled.CreatedAt, scheduled.UpdatedAt = base, base
+
+	f, err := ProjectFeed(feedConfig, true, nil, []*models.Project{old, edited}, []*models.ProjectRelease{released, corrected, scheduled})
+	if err != nil {
+		t.Fatalf("ProjectFeed() error = %v", err)
+	}
+	var titles []string
+	for _, entry := range f.Entries {
+		titles = append(titles, entry.Title)
+	}
+	want := []string{"Edited", "Edited 2.0.0", "Old 0.9.0", "Old 1.0.0", "Old"}
+	if !slices.Equal(titles, want) {
+		t.Errorf("entries = %q, want %q", titles, want)
+	}
+	if !f.Updated.Equal(edited.UpdatedAt) {
+		t.Errorf("updated = %v, want %v", f.Updated, edited.UpdatedAt)
+	}
+	if got := f.Entries[4].Link; got != "https://example.com/portfolio/projects/old/" {
+		t.Errorf("link = %q", got)
+	}
+}
+
+func TestProjectFeedTruncates(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var projects []*models.Project
+	for i := range FeedSize + 10 {
+		at := base.Add(time.Duration(i) * time.Minute)
+		projects = append(projects, feedProject(int64(i+1), fmt.Sprint("Project ", i+1), at, at))
+	}
+	f, err := ProjectFeed(feedConfig, false, nil, projects, nil)
+	if err != nil {
+		t.Fatalf("ProjectFeed() error = %v", err)
+	}
+	if len(f.Entries) != FeedSize {
+		t.Fatalf("%d entries, want %d", len(f.Entries), FeedSize)
+	}
+	if first, last := f.Entries[0].Title, f.Entries[FeedSize-1].Title; first != "Project 60" || last != "Project 11" {
+		t.Errorf("entries run from %q to %q, want the latest %d", first, last, FeedSize)
+	}
+}
+
+func TestProjectFeedTechnologies(t *testing.T) {
+	technologies := []*models.Technology{{ID: 12, Name: "Rust"}, {ID: 3, Name: "Go"}}
+	f, err := ProjectFeed(feedConfig, false, technologies, nil, nil)
+	if err != nil {
+		t.Fatalf("ProjectFeed() error = %v", err)
+	}
+	if want := "https://example.com/portfolio/feeds/projects?tech_id=12&tech_id=3"; f.ID != want {
+		t.Errorf("id = %q, want %q", f.ID, want)
+	}
+	if want := "Portfolio: Rust, Go projects"; f.Title != want {
+		t.Errorf("title = %q, want %q", f.Title, want)
+	}
+
+	f, err = ProjectFeed(feedConfig, false, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("ProjectFeed() error = %v", err)
+	}
+	if f.ID != "https://example.com/portfolio/feeds/projects" || f.Title != "Portfolio: projects" {
+		t.Errorf("unfiltered feed = %q %q", f.ID, f.Title)
+	}
+}
+
+func TestProjectFeedEmpty(t *testing.T) {
+	f, err := ProjectFeed(feedConfig, false, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("ProjectFeed() error = %v", err)
+	}
+	if len(f.Entries) != 0 || !f.Updated.Equal(time.Unix(0, 0)) {
+		t.Errorf("empty feed has %d entries, updated %v, want none at the epoch", len(f.Entries), f.Updated)
+	}
+}
+
+func TestProjectFeedRejectsURL(t *testing.T) {
+	for _, url := range []string{"", "/portfolio/", "example.com", "https:///path"} {
+		cfg := feedConfig
+		cfg.URL = url
+		if _, err := ProjectFeed(cfg, false, nil, nil, nil); err == nil || !strings.Contains(err.Error(), "absolute") {
+			t.Errorf("ProjectFeed(%q) error = %v, want an absolute URL error", url, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package site
+
+import (
+	"fmt"
+	"gowebsite/internal/models"
+	"gowebsite/pkg/feed"
+	"html/template"
+	"io"
+	"strconv"
+)
+
+// Pages of a theme. Each is parsed with layout.html and the templates under
+// partials/, and executed with a Page.
+const (
+	PageIndex        = "index.html"
+	PageProject      = "project.html"
+	PageTechnologies = "technologies.html"
+	PageTechnology   = "technology.html"
+	PageNotFound     = "404.html"
+)
+
+var pages = []string{PageIndex, PageProject, PageTechnologies, PageTechnology, PageNotFound}
+
+// Page is the data page templates are executed with. Project is only set
+// on the project page and Technology on the technology page.
+type Page struct {
+	SiteTitle    string
+	Title        string
+	Projects     []*models.Project
+	Project      *models.Project
+	Technologies []*TechnologyEntry
+	Technology   *TechnologyEntry
+}
+
+// TechnologyEntry is a technology with the projects using it.
+type TechnologyEntry struct {
+	*models.Technology
+	Projects []*models.Project
+}
+
+// Links tells a Renderer where the pages and files it links to are.
+type Links struct {
+	// BasePath is the path the site is served under.
+	BasePath string
+	// DirLinks links pages as directories, "projects/slug/", the way static
+	// hosts serve index.html files.
+	DirLinks bool
+	// IconURL returns the URL of the icon of a technology, or an empty
+	// string when it has none.
+	IconURL func(technology *models.Technology) string
+	// AssetURL returns the URL of the file of an asset.
+	AssetURL func(asset *models.ProjectAsset) string
+}
+
+// Renderer executes the page templates of a theme. Templates link with
+// these functions:
+//
+//	url "techs"          a page by its path, "" for the home page
+//	static "style.css"   a file under static/ by its name before hashing
+//	projectURL .Project  the page of a project
+//	technologyURL .      the page of a technology
+//	iconURL .            the icon of a technology, "" without one
+//	assetURL .           the file of a project asset
+//	feedURL "atom"       the project feed in a format of FeedFormats
+type Renderer struct {
+	theme *Theme
+	links Links
+	pages map[string]*template.Template
+}
+
+// NewRenderer parses the pages of theme.
+func NewRenderer(theme *Theme, links Links) (*Renderer, error) {
+	if links.BasePath == "" || links.BasePath[len(links.BasePath)-1] != '/' {
+		links.BasePath += "/"
+	}
+	r := &Renderer{theme: theme, links: links, pages: make(map[string]*template.Template, len(pages))}
+
+	partials, err := theme.partials()
+	if err != nil {
+		return nil, fmt.Errorf("theme: %w", err)
+	}
+	for _, page := range pages {
+		files := append([]string{"layout.html", page}, partials...)
+		tmpl, err := template.New(page).Funcs(r.funcs()).ParseFS(theme, files...)
+		if err != nil {
+			return nil, fmt.Errorf("theme: %w", err)
+		}
+		r.pages[page] = tmpl
+	}
+	return r, nil
+}
+
+// Render writes page, one of the Page constants, executed with data.
+func (r *Renderer) Render(w io.Writer, page string, data *Page) error {
+	tmpl, ok := r.pages[page]
+	if !ok {
+		return fmt.Errorf("unknown page %q", page)
+	}
+	return tmpl.ExecuteTemplate(w, page, data)
+}
+
+func (r *Renderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"url": r.url,
+		"static": func(name string) (string, error) {
+			hashed, ok := r.theme.Static(name)
+			if !ok {
+				return "", fmt.Errorf("no static file %q in the theme", name)
+			}
+			return r.links.BasePath + hashed, nil
+		},
+		"projectURL": func(project *models.Project) string {
+			return r.url("projects/" + project.Slug)
+		},
+		"technologyURL": func(technology *models.Technology) string {
+			return r.url("techs/" + strconv.FormatInt(technology.ID, 10))
+		},
+		"feedURL": func(format feed.Format) string {
+			return r.links.BasePath + FeedPath(format)
+		},
+		"iconURL":  r.links.IconURL,
+		"assetURL": r.links.AssetURL,
+	}
+}
+
+// url returns the link to the page at path, relative to the base path.
+func (r *Renderer) url(path string) string {
+	if path != "" && r.links.DirLinks {
+		path += "/"
+	}
+	return r.links.BasePath + path
+}
@@ -0,0 +1,358 @@
+// Package site renders the portfolio into HTML pages from the templates of
+// a theme, both for the web pages of the server and for a static build any
+// static host can serve without the API.
+//
+// A theme has layout.html, the page templates listed by the Page
+// constants, shared templates under partials/ and static files under
+// static/. Static files get a content hash in their name, so they can be
+// cached forever; templates refer to them by their original name with the
+// static function:
+//
+//	<link rel="stylesheet" href="{{static "style.css"}}">
+package site
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gowebsite/internal/models"
+	"gowebsite/pkg/svg"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SiteConfig struct {
+	Title string `env:"SITE_TITLE" env-default:"Portfolio"`
+	// URL is the absolute address of the home page, which the feeds link
+	// to and take the host of their entry IDs from. It must be set before
+	// the feeds are published: the IDs change with it.
+	URL string `env:"SITE_URL" env-default:"http://localhost:8080/"`
+	// BasePath is the path the static build is served under, links are
+	// prefixed with it.
+	BasePath string `env:"SITE_BASE_PATH" env-default:"/"`
+	// ThemeDir overrides templates and static files of the default theme
+	// with the files of the same name in it.
+	ThemeDir  string `env:"SITE_THEME_DIR"`
+	OutputDir string `env:"SITE_OUTPUT_DIR" env-default:"./public"`
+}
+
+// LocalURL is the default of SiteConfig.URL, for local development only.
+const LocalURL = "http://localhost:8080/"
+
+// Portfolio is where the static build takes its content from, see
+// service.PortfolioService.
+type Portfolio interface {
+	ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, *models.PageInfo, error)
+	ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, *models.PageInfo, error)
+	ListRecentReleases(ctx context.Context, filter *models.ProjectFilter) ([]*models.ProjectRelease, error)
+	OpenAsset(ctx context.Context, asset *models.ProjectAsset) (io.ReadCloser, error)
+}
+
+// Manifest describes a build. It is written to manifest.json of the output
+// directory, which also marks the directory as safe to replace.
+type Manifest struct {
+	BuiltAt time.Time `json:"built_at"`
+	// Static maps the files under static/ of the theme to their hashed
+	// names.
+	Static map[string]string `json:"static"`
+	// Files lists every file of the build, slash separated and relative to
+	// the output directory.
+	Files []string `json:"files"`
+}
+
+const manifestName = "manifest.json"
+
+// hashLen is the number of hex digits of the content hash in file names.
+const hashLen = 12
+
+// Entries pairs every technology with the projects using it.
+func Entries(technologies []*models.Technology, projects []*models.Project) []*TechnologyEntry {
+	entries := make([]*TechnologyEntry, len(technologies))
+	byID := make(map[int64]*TechnologyEntry, len(technologies))
+	for i, technology := range technologies {
+		entries[i] = &TechnologyEntry{Technology: technology, Projects: []*models.Project{}}
+		byID[technology.ID] = entries[i]
+	}
+	for _, project := range projects {
+		for _, technology := range project.Technologies {
+			if entry, ok := byID[technology.ID]; ok {
+				entry.Projects = append(entry.Projects, project)
+			}
+		}
+	}
+	return entries
+}
+
+type builder struct {
+	dir      string
+	manifest *Manifest
+	icons    map[int64]string
+	assets   map[int64]string
+}
+
+// Build renders the live projects and technologies into cfg.OutputDir. The
+// site is built next to it and swapped in when complete, so a failed build
+// leaves the previous one in place. An existing output directory is only
+// replaced if it holds a previous build.
+func Build(ctx context.Context, portfolio Portfolio, cfg SiteConfig) (*Manifest, error) {
+	if !strings.HasSuffix(cfg.BasePath, "/") {
+		cfg.BasePath += "/"
+	}
+	theme, err := LoadTheme(cfg.ThemeDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOutputDir(cfg.OutputDir); err != nil {
+		return nil, err
+	}
+
+	technologies, _, err := portfolio.ListTechnologies(ctx, &models.TechnologyFilter{})
+	if err != nil {
+		return nil, err
+	}
+	projects, _, err := portfolio.ListProjects(ctx, &models.ProjectFilter{})
+	if err != nil {
+		return nil, err
+	}
+	releases, err := portfolio.ListRecentReleases(ctx, &models.ProjectFilter{Limit: FeedSize})
+	if err != nil {
+		return nil, err
+	}
+
+	parent := filepath.Dir(filepath.Clean(cfg.OutputDir))
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return nil, fmt.Errorf("site.Build: %w", err)
+	}
+	dir, err := os.MkdirTemp(parent, "."+filepath.Base(cfg.OutputDir)+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("site.Build: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chmod(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("site.Build: %w", err)
+	}
+
+	b := &builder{
+		dir:      dir,
+		manifest: &Manifest{BuiltAt: time.Now().UTC(), Static: map[string]string{}, Files: []string{}},
+		icons:    map[int64]string{},
+		assets:   map[int64]string{},
+	}
+	for _, hashed := range theme.StaticFiles() {
+		data, err := theme.ReadStatic(hashed)
+		if err != nil {
+			return nil, fmt.Errorf("site.Build: theme: %w", err)
+		}
+		if err := b.write(hashed, data); err != nil {
+			return nil, err
+		}
+	}
+	b.manifest.Static = theme.static
+	if err := b.writeIcons(technologies); err != nil {
+		return nil, err
+	}
+	if err := b.copyAssets(ctx, portfolio, projects); err != nil {
+		return nil, err
+	}
+
+	renderer, err := NewRenderer(theme, Links{
+		BasePath: cfg.BasePath,
+		DirLinks: true,
+		IconURL: func(technology *models.Technology) string {
+			if name, ok := b.icons[technology.ID]; ok {
+				return cfg.BasePath + name
+			}
+			return ""
+		},
+		AssetURL: func(asset *models.ProjectAsset) string {
+			return cfg.BasePath + b.assets[asset.ID]
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := b.renderPages(renderer, cfg.Title, technologies, projects); err != nil {
+		return nil, err
+	}
+	if err := b.writeFeeds(cfg, projects, releases); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("site.Build: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestName), data, 0o644); err != nil {
+		return nil, fmt.Errorf("site.Build: %w", err)
+	}
+	if err := os.RemoveAll(cfg.OutputDir); err != nil {
+		return nil, fmt.Errorf("site.Build: %w", err)
+	}
+	if err := os.Rename(dir, cfg.OutputDir); err != nil {
+		return nil, fmt.Errorf("site.Build: %w", err)
+	}
+	return b.manifest, nil
+}
+
+// checkOutputDir refuses to build into a directory that holds anything
+// but a previous build, as the build replaces it.
+func checkOutputDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) || err == nil && len(entries) == 0 {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("site.Build: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, manifestName)); err != nil {
+		return fmt.Errorf("output directory %s is not empty and holds no previous build", dir)
+	}
+	return nil
+}
+
+// writeIcons writes the sanitized icons of the technologies. Icons that
+// fail sanitization are left out, as the API does.
+func (b *builder) writeIcons(technologies []*models.Technology) error {
+	for _, technology := range technologies {
+		if !technology.Svg.Valid || technology.Svg.String == "" {
+			continue
+		}
+		icon, err := svg.Sanitize(technology.Svg.String)
+		if err != nil {
+			continue
+		}
+		name := "icons/" + contentHash([]byte(icon)) + ".svg"
+		if err := b.write(name, []byte(icon)); err != nil {
+			return err
+		}
+		b.icons[technology.ID] = name
+	}
+	return nil
+}
+
+func (b *builder) copyAssets(ctx context.Context, portfolio Portfolio, projects []*models.Project) error {
+	for _, project := range projects {
+		for _, asset := range project.Assets {
+			file, err := portfolio.OpenAsset(ctx, asset)
+			if err != nil {
+				return err
+			}
+			data, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				return fmt.Errorf("site.Build: asset %d: %w", asset.ID, err)
+			}
+			name := "media/" + contentHash(data) + strings.ToLower(path.Ext(asset.StorageKey))
+			if err := b.write(name, data); err != nil {
+				return err
+			}
+			b.assets[asset.ID] = name
+		}
+	}
+	return nil
+}
+
+func (b *builder) renderPages(renderer *Renderer, title string, technologies []*models.Technology, projects []*models.Project) error {
+	entries := Entries(technologies, projects)
+	base := Page{SiteTitle: title, Projects: projects, Technologies: entries}
+
+	index := base
+	if err := b.render(renderer, PageIndex, "index.html", &index); err != nil {
+		return err
+	}
+	notFound := base
+	notFound.Title = "Not found"
+	if err := b.render(renderer, PageNotFound, "404.html", &notFound); err != nil {
+		return err
+	}
+	technologiesPage := base
+	technologiesPage.Title = "Technologies"
+	if err := b.render(renderer, PageTechnologies, "techs/index.html", &technologiesPage); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		technologyPage := base
+		technologyPage.Title = entry.Name
+		technologyPage.Technology = entry
+		file := "techs/" + strconv.FormatInt(entry.ID, 10) + "/index.html"
+		if err := b.render(renderer, PageTechnology, file, &technologyPage); err != nil {
+			return err
+		}
+	}
+	for _, project := range projects {
+		projectPage := base
+		projectPage.Title = project.Title
+		projectPage.Project = project
+		if err := b.render(renderer, PageProject, "projects/"+project.Slug+"/index.html", &projectPage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFeeds writes the project feed in every format.
+func (b *builder) writeFeeds(cfg SiteConfig, projects []*models.Project, releases []*models.ProjectRelease) error {
+	projectFeed, err := ProjectFeed(cfg, true, nil, projects, releases)
+	if err != nil {
+		return fmt.Errorf("site.Build: %w", err)
+	}
+	for _, format := range FeedFormats {
+		file := FeedPath(format)
+		projectFeed.FeedURL = strings.TrimSuffix(cfg.URL, "/") + "/" + file
+		var buf bytes.Buffer
+		if err := projectFeed.Write(&buf, format); err != nil {
+			return fmt.Errorf("site.Build: %s: %w", file, err)
+		}
+		if err := b.write(file, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builder) render(renderer *Renderer, page, file string, data *Page) error {
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, page, data); err != nil {
+		return fmt.Errorf("site.Build: %s: %w", file, err)
+	}
+	return b.write(file, buf.Bytes())
+}
+
+// write writes a file of the site, name slash separated. Files with the
+// same hashed name have the same content, so they are written once.
+func (b *builder) write(name string, data []byte) error {
+	target := filepath.Join(b.dir, filepath.FromSlash(name))
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("site.Build: %w", err)
+	}
+	if err := os.WriteFile(target, data, 0o644); err != nil {
+		return fmt.Errorf("site.Build: %w", err)
+	}
+	b.manifest.Files = append(b.manifest.Files, name)
+	return nil
+}
+
+// hashedName inserts the content hash of data before the extension of
+// name: static/style.css becomes static/style.0123456789ab.css.
+func hashedName(name string, data []byte) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + contentHash(data) + ext
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:hashLen]
+}
@@ -0,0 +1,150 @@
+package site
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// defaultTheme holds the templates and the static files of the site.
+//
+//go:embed theme
+var defaultTheme embed.FS
+
+// Theme is a theme directory laid over the default theme: a file of the
+// directory replaces the default file with the same name, so a theme only
+// needs the files it changes.
+type Theme struct {
+	upper fs.FS
+	lower fs.FS
+	// static maps the files under static/ to their hashed names, hashed
+	// the other way round.
+	static map[string]string
+	hashed map[string]string
+}
+
+// LoadTheme lays dir, if not empty, over the default theme and hashes its
+// static files.
+func LoadTheme(dir string) (*Theme, error) {
+	lower, err := fs.Sub(defaultTheme, "theme")
+	if err != nil {
+		return nil, err
+	}
+	t := &Theme{lower: lower, static: map[string]string{}, hashed: map[string]string{}}
+	if dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("theme: %w", err)
+		}
+		t.upper = os.DirFS(dir)
+	}
+
+	err = fs.WalkDir(t, "static", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(t, name)
+		if err != nil {
+			return err
+		}
+		hashed := hashedName(name, data)
+		t.static[strings.TrimPrefix(name, "static/")] = hashed
+		t.hashed[hashed] = name
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("theme: %w", err)
+	}
+	return t, nil
+}
+
+func (t *Theme) Open(name string) (fs.File, error) {
+	if t.upper != nil {
+		file, err := t.upper.Open(name)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return t.lower.Open(name)
+}
+
+// ReadDir merges the entries of the directory in both layers, sorted by
+// name.
+func (t *Theme) ReadDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	found := false
+	for _, layer := range []fs.FS{t.upper, t.lower} {
+		if layer == nil {
+			continue
+		}
+		layerEntries, err := fs.ReadDir(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		for _, entry := range layerEntries {
+			if !slices.ContainsFunc(entries, func(e fs.DirEntry) bool { return e.Name() == entry.Name() }) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// Static returns the hashed name of a file under static/, e.g.
+// "static/style.0123456789ab.css" for "style.css".
+func (t *Theme) Static(name string) (string, bool) {
+	hashed, ok := t.static[name]
+	return hashed, ok
+}
+
+// StaticFiles returns the hashed names of the static files, sorted.
+func (t *Theme) StaticFiles() []string {
+	names := make([]string, 0, len(t.hashed))
+	for hashed := range t.hashed {
+		names = append(names, hashed)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ReadStatic returns the content of a static file by its hashed name.
+func (t *Theme) ReadStatic(hashed string) ([]byte, error) {
+	name, ok := t.hashed[hashed]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: hashed, Err: fs.ErrNotExist}
+	}
+	return fs.ReadFile(t, name)
+}
+
+// partials lists the templates under partials/.
+func (t *Theme) partials() ([]string, error) {
+	entries, err := t.ReadDir("partials")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && path.Ext(entry.Name()) == ".html" {
+			names = append(names, "partials/"+entry.Name())
+		}
+	}
+	return names, nil
+}
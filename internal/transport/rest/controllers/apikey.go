@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"gowebsite/internal/models"
+	"gowebsite/internal/transport/rest/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, request *models.APIKeyRequest) (*models.CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+}
+
+type APIKeyController struct {
+	service APIKeyService
+}
+
+func NewAPIKeyController(service APIKeyService) *APIKeyController {
+	return &APIKeyController{service: service}
+}
+
+// @Summary Create API key
+// @Description Create a scoped API key for automation clients. The key is only returned once
+// @Tags Auth
+// @Accept json
+// @Param key body models.APIKeyRequest true "API key"
+// @Produce json
+// @Success 201 {object} models.CreatedAPIKey "API key"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Admin role required"
+// @Failure 422 {object} problem.Problem "Invalid scope, expiry or quota"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Router /auth/api-keys [post]
+func (kc *APIKeyController) CreateAPIKey(c *gin.Context) {
+	var request models.APIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_body", "Invalid request body"))
+		return
+	}
+
+	key, err := kc.service.CreateAPIKey(c.Request.Context(), &request)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(201, key)
+}
+
+// @Summary API key list
+// @Description Get API keys without their secrets
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} models.APIKey "API keys"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Admin role required"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Router /auth/api-keys [get]
+func (kc *APIKeyController) GetListAPIKeys(c *gin.Context) {
+	keys, err := kc.service.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, keys)
+}
+
+// @Summary Revoke API key
+// @Description Revoke API key
+// @Tags Auth
+// @Param id path int true "API key ID"
+// @Produce json
+// @Success 200 {object} map[string]any "Message"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Admin role required"
+// @Failure 404 {object} problem.Problem "API key not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Router /auth/api-keys/{id} [delete]
+func (kc *APIKeyController) RevokeAPIKey(c *gin.Context) {
+	keyID, ok := parseID(c, "API key ID")
+	if !ok {
+		return
+	}
+
+	if err := kc.service.RevokeAPIKey(c.Request.Context(), keyID); err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "API key revoked successfully"})
+}
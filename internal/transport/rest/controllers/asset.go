@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"errors"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/internal/transport/rest/problem"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseAssetID reads the :assetId path parameter like parseID.
+func parseAssetID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("assetId"), 10, 64)
+	if err != nil {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_id", "Asset ID is not integer"))
+		return 0, false
+	}
+	return id, true
+}
+
+// @Summary Upload project asset
+// @Description Upload a screenshot or another asset of a project. The type is detected from the content
+// @Tags Portfolio
+// @Accept multipart/form-data
+// @Param id path int true "Project ID"
+// @Param file formData file true "Asset file"
+// @Param caption formData string false "Caption"
+// @Param position formData int false "Position to insert the asset at, the end by default"
+// @Produce json
+// @Success 201 {object} models.ProjectAsset "Asset"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Failure 404 {object} problem.Problem "Project not found"
+// @Failure 413 {object} problem.Problem "Asset too large"
+// @Failure 415 {object} problem.Problem "Asset type not allowed"
+// @Failure 422 {object} problem.Problem "Invalid fields"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/projects/{id}/assets [post]
+func (pc *PortfolioController) UploadAsset(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			problem.Abort(c, problem.New(http.StatusRequestEntityTooLarge, "asset_too_large", "Request body is too large"))
+			return
+		}
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_body", "Request must be multipart/form-data with a file field"))
+		return
+	}
+
+	upload := &models.AssetUpload{Filename: header.Filename, Size: header.Size, Caption: c.PostForm("caption")}
+	if value := c.PostForm("position"); value != "" {
+		position, err := strconv.Atoi(value)
+		if err != nil {
+			problem.Error(c, apperrors.InvalidFields([]apperrors.FieldError{{
+				Field: "position", Code: "type", Message: "must be of type int",
+			}}))
+			return
+		}
+		upload.Position = &position
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	defer file.Close()
+
+	asset, err := pc.service.UploadAsset(c.Request.Context(), projectID, upload, file)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(201, asset)
+}
+
+// @Summary Project asset list
+// @Description Get the assets of a project in order
+// @Tags Portfolio
+// @Param id path int true "Project ID"
+// @Produce json
+// @Success 200 {array} models.ProjectAsset "Assets"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Project not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Router /portfolio/projects/{id}/assets [get]
+func (pc *PortfolioController) GetListAssets(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+
+	assets, err := pc.service.ListAssets(c.Request.Context(), projectID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, assets)
+}
+
+// @Summary Update project asset
+// @Description Change the caption of an asset or move it to another position
+// @Tags Portfolio
+// @Accept json
+// @Param id path int true "Project ID"
+// @Param assetId path int true "Asset ID"
+// @Param asset body models.AssetUpdate true "Asset update"
+// @Produce json
+// @Success 200 {object} models.ProjectAsset "Asset"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Failure 404 {object} problem.Problem "Asset not found"
+// @Failure 422 {object} problem.Problem "Invalid fields"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/projects/{id}/assets/{assetId} [patch]
+func (pc *PortfolioController) PatchAsset(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+	assetID, ok := parseAssetID(c)
+	if !ok {
+		return
+	}
+
+	var update models.AssetUpdate
+	if !bindJSON(c, &update) {
+		return
+	}
+
+	asset, err := pc.service.PatchAsset(c.Request.Context(), projectID, assetID, &update)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, asset)
+}
+
+// @Summary Delete project asset
+// @Description Delete an asset and its file
+// @Tags Portfolio
+// @Param id path int true "Project ID"
+// @Param assetId path int true "Asset ID"
+// @Produce json
+// @Success 200 {object} map[string]any "Message"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Failure 404 {object} problem.Problem "Asset not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/projects/{id}/assets/{assetId} [delete]
+func (pc *PortfolioController) DeleteAsset(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+	assetID, ok := parseAssetID(c)
+	if !ok {
+		return
+	}
+
+	if err := pc.service.DeleteAsset(c.Request.Context(), projectID, assetID); err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Asset deleted successfully"})
+}
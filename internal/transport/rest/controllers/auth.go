@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"gowebsite/internal/models"
+	"gowebsite/internal/transport/rest/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, credentials *models.Credentials) (*models.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+}
+
+type AuthController struct {
+	service AuthService
+}
+
+func NewAuthController(service AuthService) *AuthController {
+	return &AuthController{service: service}
+}
+
+// @Summary Login
+// @Description Exchange email and password for an access and a refresh token
+// @Tags Auth
+// @Accept json
+// @Param credentials body models.Credentials true "Credentials"
+// @Produce json
+// @Success 200 {object} models.TokenPair "Tokens"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Invalid credentials"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Router /auth/login [post]
+func (ac *AuthController) Login(c *gin.Context) {
+	var credentials models.Credentials
+	if err := c.ShouldBindJSON(&credentials); err != nil {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_body", "Invalid request body"))
+		return
+	}
+
+	tokens, err := ac.service.Login(c.Request.Context(), &credentials)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, tokens)
+}
+
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new token pair. Refresh tokens are single use
+// @Tags Auth
+// @Accept json
+// @Param refresh body models.RefreshRequest true "Refresh token"
+// @Produce json
+// @Success 200 {object} models.TokenPair "Tokens"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Invalid refresh token"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Router /auth/refresh [post]
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var request models.RefreshRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_body", "Invalid request body"))
+		return
+	}
+
+	tokens, err := ac.service.Refresh(c.Request.Context(), request.RefreshToken)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, tokens)
+}
+
+// @Summary Logout
+// @Description Revoke a refresh token
+// @Tags Auth
+// @Accept json
+// @Param refresh body models.RefreshRequest true "Refresh token"
+// @Produce json
+// @Success 200 {object} map[string]any "Message"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Router /auth/logout [post]
+func (ac *AuthController) Logout(c *gin.Context) {
+	var request models.RefreshRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_body", "Invalid request body"))
+		return
+	}
+
+	if err := ac.service.Logout(c.Request.Context(), request.RefreshToken); err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Logged out successfully"})
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/transport/rest/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindJSON decodes the request body into v. A field of the wrong type is
+// reported as a 422 field error like the rest of validation, anything else
+// that is not valid JSON as 400. It returns false on failure.
+func bindJSON(c *gin.Context, v any) bool {
+	err := c.ShouldBindJSON(v)
+	if err == nil {
+		return true
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		problem.Error(c, apperrors.InvalidFields([]apperrors.FieldError{{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: fmt.Sprintf("must be of type %s", typeErr.Type),
+		}}))
+		return false
+	}
+	problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_body", "Invalid request body"))
+	return false
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"fmt"
+	"gowebsite/internal/models"
+	"gowebsite/internal/transport/rest/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Export portfolio
+// @Description Export every technology and project as a versioned bundle, projects referring to technologies by name. The bundle is imported with the import command.
+// @Tags Portfolio
+// @Param format query string false "Bundle format" Enums(json, yaml) default(json)
+// @Produce json
+// @Produce application/yaml
+// @Success 200 {object} models.Bundle "Bundle"
+// @Header 200 {string} Content-Disposition "Suggested file name"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Router /portfolio/export [get]
+func (pc *PortfolioController) ExportBundle(c *gin.Context) {
+	query := &models.BundleQuery{}
+	if !bindQuery(c, query) {
+		return
+	}
+	if query.Format == "" {
+		query.Format = models.BundleJSON
+	}
+	if !query.Format.IsValid() {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_format", "Format must be json or yaml"))
+		return
+	}
+
+	bundle, err := pc.service.ExportBundle(c.Request.Context())
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	contentType := "application/json; charset=utf-8"
+	if query.Format == models.BundleYAML {
+		contentType = "application/yaml; charset=utf-8"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="portfolio-%s.%s"`,
+		bundle.ExportedAt.Format("20060102-150405"), query.Format))
+	c.Status(http.StatusOK)
+	if err := models.EncodeBundle(c.Writer, bundle, query.Format); err != nil {
+		// The status is sent already, all that is left is to log it.
+		_ = c.Error(err)
+	}
+}
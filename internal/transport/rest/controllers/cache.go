@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"gowebsite/internal/transport/rest/problem"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statsMaxAge is how long clients and shared caches may reuse statistics.
+const statsMaxAge = 5 * time.Minute
+
+// iconMaxAge is long because icons rarely change; the ETag lets clients
+// revalidate cheaply once it runs out.
+const iconMaxAge = 7 * 24 * time.Hour
+
+// revalidate lets clients keep responses but makes them check with us
+// before every reuse, which a matching ETag turns into a cheap 304.
+const revalidate = "no-cache"
+
+func publicMaxAge(maxAge time.Duration) string {
+	return "public, max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+}
+
+// conditionalJSON writes body as JSON, see conditional.
+func conditionalJSON(c *gin.Context, cacheControl string, lastModified time.Time, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+	conditional(c, cacheControl, lastModified, "application/json; charset=utf-8", data)
+}
+
+// conditional writes data with a strong ETag computed from its content and,
+// unless lastModified is zero, a Last-Modified date. It answers 304 when
+// If-None-Match or, without one, If-Modified-Since shows that the client's
+// copy is current.
+func conditional(c *gin.Context, cacheControl string, lastModified time.Time, contentType string, data []byte) {
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", cacheControl)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(c, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		return etagMatches(header, etag)
+	}
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// Last-Modified has a resolution of one second.
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches implements the weak comparison If-None-Match calls for.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
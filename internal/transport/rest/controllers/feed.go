@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/internal/site"
+	"gowebsite/internal/transport/rest/problem"
+	"gowebsite/pkg/feed"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedService interface {
+	ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, *models.PageInfo, error)
+	ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, *models.PageInfo, error)
+	ListRecentReleases(ctx context.Context, filter *models.ProjectFilter) ([]*models.ProjectRelease, error)
+}
+
+// FeedController serves the feed of projects and releases of
+// site.ProjectFeed.
+type FeedController struct {
+	service FeedService
+	cfg     site.SiteConfig
+}
+
+func NewFeedController(service FeedService, cfg site.SiteConfig) *FeedController {
+	return &FeedController{service: service, cfg: cfg}
+}
+
+// Projects returns the handler of the project feed in format. The tech_id
+// query parameter narrows it to the projects, and their releases, using
+// any of the technologies.
+func (fc *FeedController) Projects(format feed.Format) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filter models.FeedFilter
+		if !bindQuery(c, &filter) {
+			return
+		}
+
+		var technologies []*models.Technology
+		if filter.TechnologiesID != nil && len(*filter.TechnologiesID) > 0 {
+			var err error
+			technologies, _, err = fc.service.ListTechnologies(c.Request.Context(), &models.TechnologyFilter{TechnologiesID: filter.TechnologiesID})
+			if err != nil {
+				problem.Error(c, err)
+				return
+			}
+			if missing := missingTechnology(*filter.TechnologiesID, technologies); missing != 0 {
+				problem.Error(c, apperrors.NotFound("technology_not_found", "Technology with id %d not found", missing))
+				return
+			}
+		}
+
+		projects, _, err := fc.service.ListProjects(c.Request.Context(), &models.ProjectFilter{
+			TechnologiesID: filter.TechnologiesID,
+			Sort:           "-updated_at",
+			Limit:          site.FeedSize,
+		})
+		if err != nil {
+			problem.Error(c, err)
+			return
+		}
+		releases, err := fc.service.ListRecentReleases(c.Request.Context(), &models.ProjectFilter{
+			TechnologiesID: filter.TechnologiesID,
+			Limit:          site.FeedSize,
+		})
+		if err != nil {
+			problem.Error(c, err)
+			return
+		}
+
+		projectFeed, err := site.ProjectFeed(fc.cfg, false, technologies, projects, releases)
+		if err != nil {
+			problem.Error(c, err)
+			return
+		}
+		// The feed links to itself with its own parameters only, not with
+		// the ones it ignores such as tracking parameters.
+		self := url.URL{Path: c.Request.URL.Path}
+		if ids := c.QueryArray("tech_id"); len(ids) > 0 {
+			self.RawQuery = url.Values{"tech_id": ids}.Encode()
+		}
+		projectFeed.FeedURL = strings.TrimSuffix(fc.cfg.URL, "/") + self.RequestURI()
+		var buf bytes.Buffer
+		if err := projectFeed.Write(&buf, format); err != nil {
+			problem.Error(c, err)
+			return
+		}
+		conditional(c, revalidate, projectFeed.Updated, format.ContentType(), buf.Bytes())
+	}
+}
+
+// missingTechnology returns the first of ids that technologies lack, 0
+// when all are there.
+func missingTechnology(ids []int64, technologies []*models.Technology) int64 {
+	found := make(map[int64]bool, len(technologies))
+	for _, technology := range technologies {
+		found[technology.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return id
+		}
+	}
+	return 0
+}
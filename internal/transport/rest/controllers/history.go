@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"gowebsite/internal/transport/rest/problem"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseRevisionID reads the :revisionId path parameter like parseID.
+func parseRevisionID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("revisionId"), 10, 64)
+	if err != nil {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_id", "Revision ID is not integer"))
+		return 0, false
+	}
+	return id, true
+}
+
+// @Summary Project history
+// @Description Get the revisions of a project, newest first, with who made them and the fields they changed
+// @Tags Portfolio
+// @Param id path int true "Project ID"
+// @Produce json
+// @Success 200 {array} models.Revision "Revisions"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Project not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/projects/{id}/history [get]
+func (pc *PortfolioController) GetProjectHistory(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+
+	revisions, err := pc.service.ProjectHistory(c.Request.Context(), projectID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, revisions)
+}
+
+// @Summary Revert Project
+// @Description Bring a project back to the state it had after a revision
+// @Tags Portfolio
+// @Param id path int true "Project ID"
+// @Param revisionId path int true "Revision ID"
+// @Produce json
+// @Success 200 {object} models.Project "Reverted project"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Project or revision not found"
+// @Failure 409 {object} problem.Problem "Revision deleted the project"
+// @Failure 422 {object} problem.Problem "Revision is no longer valid"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/projects/{id}/history/{revisionId}/revert [post]
+func (pc *PortfolioController) RevertProject(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+	revisionID, ok := parseRevisionID(c)
+	if !ok {
+		return
+	}
+
+	project, err := pc.service.RevertProject(c.Request.Context(), projectID, revisionID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, project)
+}
+
+// @Summary Technology history
+// @Description Get the revisions of a technology, newest first, with who made them and the fields they changed
+// @Tags Portfolio
+// @Param id path int true "Technology ID"
+// @Produce json
+// @Success 200 {array} models.Revision "Revisions"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Technology not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/techs/{id}/history [get]
+func (pc *PortfolioController) GetTechnologyHistory(c *gin.Context) {
+	technologyID, ok := parseID(c, "Technology ID")
+	if !ok {
+		return
+	}
+
+	revisions, err := pc.service.TechnologyHistory(c.Request.Context(), technologyID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, revisions)
+}
+
+// @Summary Revert Technology
+// @Description Bring a technology back to the state it had after a revision
+// @Tags Portfolio
+// @Param id path int true "Technology ID"
+// @Param revisionId path int true "Revision ID"
+// @Produce json
+// @Success 200 {object} models.Technology "Reverted technology"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Technology or revision not found"
+// @Failure 409 {object} problem.Problem "Revision deleted the technology"
+// @Failure 422 {object} problem.Problem "Revision is no longer valid"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/techs/{id}/history/{revisionId}/revert [post]
+func (pc *PortfolioController) RevertTechnology(c *gin.Context) {
+	technologyID, ok := parseID(c, "Technology ID")
+	if !ok {
+		return
+	}
+	revisionID, ok := parseRevisionID(c)
+	if !ok {
+		return
+	}
+
+	technology, err := pc.service.RevertTechnology(c.Request.Context(), technologyID, revisionID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, technology)
+}
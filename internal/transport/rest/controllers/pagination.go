@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"fmt"
+	"gowebsite/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setPageHeaders writes the total count and an RFC 8288 Link header for a
+// list page. The next page is always linked by cursor, which is also sent
+// as X-Next-Cursor, so any page can continue as a keyset page. Offset pages
+// also link to the previous and last page by offset for older clients.
+func setPageHeaders(c *gin.Context, info *models.PageInfo) {
+	c.Header("X-Total-Count", strconv.FormatInt(info.Total, 10))
+	if info.Limit == 0 {
+		return
+	}
+
+	links := []string{pageLink(c, "first", nil)}
+	if info.NextCursor != "" {
+		c.Header("X-Next-Cursor", info.NextCursor)
+		links = append(links, pageLink(c, "next", map[string]string{"cursor": info.NextCursor}))
+	}
+	if c.Query("cursor") == "" {
+		limit, offset := info.Limit, info.Offset
+		if offset > 0 {
+			prev := uint64(0)
+			if offset > limit {
+				prev = offset - limit
+			}
+			links = append(links, pageLink(c, "prev", map[string]string{"offset": strconv.FormatUint(prev, 10)}))
+		}
+		if info.Total > 0 {
+			last := (uint64(info.Total) - 1) / limit * limit
+			links = append(links, pageLink(c, "last", map[string]string{"offset": strconv.FormatUint(last, 10)}))
+		}
+	}
+	c.Header("Link", strings.Join(links, ", "))
+}
+
+// pageLink builds a link to the current URL with the paging parameters
+// replaced by params.
+func pageLink(c *gin.Context, rel string, params map[string]string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Del("cursor")
+	query.Del("offset")
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	u.RawQuery = query.Encode()
+	return fmt.Sprintf("<%s>; rel=%q", u.RequestURI(), rel)
+}
@@ -2,33 +2,66 @@ package controllers
 
 import (
 	"context"
-	"fmt"
 	"gowebsite/internal/models"
+	"gowebsite/internal/transport/rest/problem"
+	"gowebsite/pkg/logger"
+	"io"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 type PortfolioService interface {
 	CreateTechnology(ctx context.Context, technology *models.Technology) (int64, error)
 	GetTechnology(ctx context.Context, id int64) (*models.Technology, error)
-	ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, error)
+	ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, *models.PageInfo, error)
+	TechnologyIcon(ctx context.Context, id int64) ([]byte, error)
+	TechnologyStats(ctx context.Context, filter *models.TechnologyStatsFilter) ([]*models.TechnologyStats, error)
 	DeleteTechnology(ctx context.Context, id int64) error
+	RestoreTechnology(ctx context.Context, id int64) (*models.Technology, error)
 	PatchTechnology(ctx context.Context, technology *models.Technology) error
 	CreateProject(ctx context.Context, project *models.Project) (int64, error)
 	GetProject(ctx context.Context, id int64) (*models.Project, error)
-	ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, error)
+	ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, *models.PageInfo, error)
 	DeleteProject(ctx context.Context, id int64) error
-	PatchProject(ctx context.Context, project *models.Project, projectUpdate *models.Project) error
+	RestoreProject(ctx context.Context, id int64) (*models.Project, error)
+	PatchProject(ctx context.Context, id int64, projectUpdate *models.Project) error
+	UploadAsset(ctx context.Context, projectID int64, upload *models.AssetUpload, file io.Reader) (*models.ProjectAsset, error)
+	ListAssets(ctx context.Context, projectID int64) ([]*models.ProjectAsset, error)
+	PatchAsset(ctx context.Context, projectID, assetID int64, update *models.AssetUpdate) (*models.ProjectAsset, error)
+	DeleteAsset(ctx context.Context, projectID, assetID int64) error
+	ProjectHistory(ctx context.Context, id int64) ([]*models.Revision, error)
+	TechnologyHistory(ctx context.Context, id int64) ([]*models.Revision, error)
+	RevertProject(ctx context.Context, id, revisionID int64) (*models.Project, error)
+	RevertTechnology(ctx context.Context, id, revisionID int64) (*models.Technology, error)
+	ListReleases(ctx context.Context, projectID int64, filter *models.ReleaseFilter) ([]*models.Release, *models.PageInfo, error)
+	GetRelease(ctx context.Context, projectID, id int64) (*models.Release, error)
+	CreateRelease(ctx context.Context, projectID int64, release *models.Release) (*models.Release, error)
+	PatchRelease(ctx context.Context, projectID, id int64, update *models.ReleaseUpdate) (*models.Release, error)
+	DeleteRelease(ctx context.Context, projectID, id int64) error
+	ExportBundle(ctx context.Context) (*models.Bundle, error)
 }
 
 type PortfolioController struct {
 	service PortfolioService
-	ctx     context.Context
 }
 
-func NewPortfolioController(ctx context.Context, service PortfolioService) *PortfolioController {
-	return &PortfolioController{service: service, ctx: ctx}
+func NewPortfolioController(service PortfolioService) *PortfolioController {
+	return &PortfolioController{service: service}
+}
+
+// parseID reads the :id path parameter and writes a 400 problem when it is
+// not an integer.
+func parseID(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_id", name+" is not integer"))
+		return 0, false
+	}
+	return id, true
 }
 
 // @Summary Technology list
@@ -36,64 +69,145 @@ func NewPortfolioController(ctx context.Context, service PortfolioService) *Port
 // @Tags Portfolio
 // @Accept json
 // @Param tech_id query []int64 false "Technology ID"
-// @Param sort_field query string false "Sort field"
-// @Param sort_order query string false "Sort order"
-// @Param limit query int false "Limit of projects"
-// @Param Offset query int false "Offset of projects"
+// @Param sort query string false "Comma separated sort fields, prefix with - for descending"
+// @Param sort_field query string false "Sort field (deprecated, use sort)"
+// @Param sort_order query string false "Sort order (deprecated, use sort)"
+// @Param limit query int false "Limit of technologies"
+// @Param offset query int false "Offset of technologies"
+// @Param cursor query string false "Cursor of the next page from the Link header"
+// @Param If-None-Match header string false "ETag of a cached response"
 // @Produce json
 // @Success 200 {array} models.Technology "Technology"
-// @Failure 400 {object} error "Bad request"
-// @Failure 500 {object} error "Internal error"
+// @Header 200 {string} ETag "Entity tag of the response"
+// @Success 304 "Not modified"
+// @Header 200 {integer} X-Total-Count "Total number of technologies"
+// @Header 200 {string} Link "RFC 8288 links to first, prev, next and last pages"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 500 {object} problem.Problem "Internal error"
 // @Router /portfolio/techs [get]
 func (pc *PortfolioController) GetListTechnologies(c *gin.Context) {
 	filter := &models.TechnologyFilter{}
 
-	if err := c.ShouldBindQuery(&filter); err != nil {
-		c.JSON(400, err)
+	if !bindQuery(c, filter) {
+		return
+	}
+
+	technologies, pageInfo, err := pc.service.ListTechnologies(c.Request.Context(), filter)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	setPageHeaders(c, pageInfo)
+	conditionalJSON(c, revalidate, time.Time{}, technologies)
+}
+
+// @Summary Technology icon
+// @Description Get the sanitized SVG icon of a technology
+// @Tags Portfolio
+// @Param id path int true "Technology ID"
+// @Param If-None-Match header string false "ETag of a cached icon"
+// @Produce image/svg+xml
+// @Success 200 {string} string "SVG icon"
+// @Header 200 {string} ETag "Entity tag of the icon"
+// @Header 200 {string} Cache-Control "Caching policy"
+// @Success 304 "Not modified"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Technology or icon not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Router /portfolio/techs/{id}/icon.svg [get]
+func (pc *PortfolioController) GetTechnologyIcon(c *gin.Context) {
+	technologyID, ok := parseID(c, "Technology ID")
+	if !ok {
 		return
 	}
 
-	languages, err := pc.service.ListTechnologies(pc.ctx, filter)
+	icon, err := pc.service.TechnologyIcon(c.Request.Context(), technologyID)
 	if err != nil {
-		c.JSON(500, err)
+		problem.Error(c, err)
 		return
 	}
 
-	c.JSON(200, languages)
+	// The icon is sanitized already; these keep it inert even if something
+	// slipped through when it is opened directly.
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	c.Header("X-Content-Type-Options", "nosniff")
+	conditional(c, publicMaxAge(iconMaxAge), time.Time{}, "image/svg+xml", icon)
+}
+
+// @Summary Technology statistics
+// @Description Get the number of projects using each technology by status and the technologies most often used together with it
+// @Tags Portfolio
+// @Param top query int false "Number of co-occurring technologies per technology, 5 by default, at most 50"
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Produce json
+// @Success 200 {array} models.TechnologyStats "Technology statistics"
+// @Header 200 {string} ETag "Entity tag of the response"
+// @Header 200 {string} Cache-Control "Caching policy"
+// @Success 304 "Not modified"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Router /portfolio/techs/stats [get]
+func (pc *PortfolioController) GetTechnologyStats(c *gin.Context) {
+	filter := &models.TechnologyStatsFilter{}
+
+	if !bindQuery(c, filter) {
+		return
+	}
+
+	stats, err := pc.service.TechnologyStats(c.Request.Context(), filter)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	conditionalJSON(c, publicMaxAge(statsMaxAge), time.Time{}, stats)
 }
 
 // @Summary Project list
-// @Description Get project list
+// @Description Get project list. With q, only projects matching the full-text search are listed, by relevance unless sorted otherwise, with highlights
 // @Tags Portfolio
 // @Accept json
-// @Param tech_id query []int64 false "Language ID"
+// @Param q query string false "Full-text search over title, description and technology names"
+// @Param tech_id query []int64 false "Technology ID"
+// @Param tech_match query string false "How tech_id is matched: any (default), all or none" Enums(any, all, none)
+// @Param exclude_tech_id query []int64 false "Exclude projects using any of these technologies"
 // @Param is_active query bool false "Is active"
 // @Param is_archived query bool false "Is archived"
 // @Param is_developing query bool false "Is developing"
-// @Param sort_field query string false "Sort field"
-// @Param sort_order query string false "Sort order"
+// @Param sort query string false "Comma separated sort fields, prefix with - for descending. relevance requires q"
+// @Param sort_field query string false "Sort field (deprecated, use sort)"
+// @Param sort_order query string false "Sort order (deprecated, use sort)"
 // @Param limit query int false "Limit of projects"
-// @Param Offset query int false "Offset of projects"
+// @Param offset query int false "Offset of projects"
+// @Param cursor query string false "Cursor of the next page from the Link header"
+// @Param If-None-Match header string false "ETag of a cached response"
 // @Produce json
 // @Success 200 {array} models.Project "Project"
-// @Failure 400 {object} error "Bad request"
-// @Failure 500 {object} error "Internal error"
+// @Header 200 {string} ETag "Entity tag of the response"
+// @Success 304 "Not modified"
+// @Header 200 {integer} X-Total-Count "Total number of projects"
+// @Header 200 {string} Link "RFC 8288 links to first, prev, next and last pages"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 500 {object} problem.Problem "Internal error"
 // @Router /portfolio/projects [get]
 func (pc *PortfolioController) GetListProjects(c *gin.Context) {
 	filter := &models.ProjectFilter{}
 
-	if err := c.ShouldBindQuery(&filter); err != nil {
-		c.JSON(400, err)
+	if !bindQuery(c, filter) {
 		return
 	}
 
-	projects, err := pc.service.ListProjects(pc.ctx, filter)
+	projects, pageInfo, err := pc.service.ListProjects(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(500, err)
+		problem.Error(c, err)
 		return
 	}
 
-	c.JSON(200, projects)
+	setPageHeaders(c, pageInfo)
+	conditionalJSON(c, revalidate, time.Time{}, projects)
 }
 
 // @Summary Technology
@@ -101,33 +215,30 @@ func (pc *PortfolioController) GetListProjects(c *gin.Context) {
 // @Tags Portfolio
 // @Accept json
 // @Param id path int true "Technology ID"
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Param If-Modified-Since header string false "Date of a cached response"
 // @Produce json
 // @Success 200 {object} models.Technology "Technology"
-// @Failure 404 {object} error "Technology not found"
-// @Failure 500 {object} error "Internal error"
-// @Failure 400 {object} error "Bad request"
+// @Header 200 {string} ETag "Entity tag of the response"
+// @Header 200 {string} Last-Modified "When the resource was last updated"
+// @Success 304 "Not modified"
+// @Failure 404 {object} problem.Problem "Technology not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 400 {object} problem.Problem "Bad request"
 // @Router /portfolio/techs/{id} [get]
 func (pc *PortfolioController) GetTechnology(c *gin.Context) {
-	technologyID := c.Param("id")
-
-	technologyID64, err := strconv.ParseInt(technologyID, 10, 64)
-	if err != nil {
-		c.JSON(400, gin.H{"error": "Technology ID is not integer"})
+	technologyID, ok := parseID(c, "Technology ID")
+	if !ok {
 		return
 	}
 
-	technology, err := pc.service.GetTechnology(pc.ctx, technologyID64)
+	technology, err := pc.service.GetTechnology(c.Request.Context(), technologyID)
 	if err != nil {
-		c.JSON(500, err)
+		problem.Error(c, err)
 		return
 	}
 
-	if technology == nil {
-		c.JSON(404, gin.H{"error": "Technology with id " + technologyID + " not found"})
-		return
-	}
-
-	c.JSON(200, technology)
+	conditionalJSON(c, revalidate, technology.UpdatedAt, technology)
 }
 
 // @Summary Project
@@ -135,33 +246,30 @@ func (pc *PortfolioController) GetTechnology(c *gin.Context) {
 // @Tags Portfolio
 // @Accept json
 // @Param id path int true "Project ID"
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Param If-Modified-Since header string false "Date of a cached response"
 // @Produce json
 // @Success 200 {object} models.Project "Project"
-// @Failure 400 {object} error "Bad request"
-// @Failure 404 {object} error "Project nor found"
-// @Failure 500 {object} error "Internal error"
+// @Header 200 {string} ETag "Entity tag of the response"
+// @Header 200 {string} Last-Modified "When the resource was last updated"
+// @Success 304 "Not modified"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Project not found"
+// @Failure 500 {object} problem.Problem "Internal error"
 // @Router /portfolio/projects/{id} [get]
 func (pc *PortfolioController) GetProject(c *gin.Context) {
-	projectID := c.Param("id")
-
-	projectID64, err := strconv.ParseInt(projectID, 10, 64)
-	if err != nil {
-		c.JSON(400, gin.H{"error": "ProjectID is not integer"})
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
 		return
 	}
 
-	project, err := pc.service.GetProject(pc.ctx, projectID64)
+	project, err := pc.service.GetProject(c.Request.Context(), projectID)
 	if err != nil {
-		c.JSON(500, err)
+		problem.Error(c, err)
 		return
 	}
 
-	if project == nil {
-		c.JSON(404, gin.H{"error": "Project with id " + projectID + " not found"})
-		return
-	}
-
-	c.JSON(200, project)
+	conditionalJSON(c, revalidate, project.UpdatedAt, project)
 }
 
 // @Summary Create Technology
@@ -172,20 +280,24 @@ func (pc *PortfolioController) GetProject(c *gin.Context) {
 // @Param svg body string flase "Technology svg"
 // @Produce json
 // @Success 200 {object} int64 "Technology ID"
-// @Failure 400 {object} error "Bad request"
-// @Failure 500 {object} error "Internal error"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 422 {object} problem.Problem "Invalid fields"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /portfolio/techs [post]
 func (pc *PortfolioController) CreateTechnology(c *gin.Context) {
 	var technology models.Technology
 
-	if err := c.ShouldBindJSON(&technology); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request body"})
+	if !bindJSON(c, &technology) {
 		return
 	}
 
-	technologyID, err := pc.service.CreateTechnology(pc.ctx, &technology)
+	technologyID, err := pc.service.CreateTechnology(c.Request.Context(), &technology)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to create technology"})
+		problem.Error(c, err)
 		return
 	}
 
@@ -197,7 +309,7 @@ func (pc *PortfolioController) CreateTechnology(c *gin.Context) {
 // @Tags Portfolio
 // @Accept json
 // @Param title body string true "Project title"
-// @Param version body string true "Project version"
+// @Param version body string true "Project version, recorded as its first release"
 // @Param description body string true "Project description"
 // @Param tech_id body []int64 true "Technology ID"
 // @Param isActive body bool true "Is active"
@@ -206,20 +318,24 @@ func (pc *PortfolioController) CreateTechnology(c *gin.Context) {
 // @Param links body []string false "Links"
 // @Produce json
 // @Success 200 {object} int64 "Project ID"
-// @Failure 400 {object} error "Bad request"
-// @Failure 500 {object} error "Internal error"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 422 {object} problem.Problem "Invalid fields"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /portfolio/projects [post]
 func (pc *PortfolioController) CreateProject(c *gin.Context) {
 	var project models.Project
-	if err := c.ShouldBindJSON(&project); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request body"})
+	if !bindJSON(c, &project) {
 		return
 	}
-	fmt.Printf("%+v\n", project)
+	logger.GetLoggerFromCtx(c.Request.Context()).Debug(c.Request.Context(), "Creating project", zap.Any("project", project))
 
-	projectID, err := pc.service.CreateProject(pc.ctx, &project)
+	projectID, err := pc.service.CreateProject(c.Request.Context(), &project)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to create project"})
+		problem.Error(c, err)
 		return
 	}
 
@@ -227,56 +343,59 @@ func (pc *PortfolioController) CreateProject(c *gin.Context) {
 }
 
 // @Summary Delete Technology
-// @Description Delete technology
+// @Description Move technology to the trash. Projects stop listing it until it is restored
 // @Tags Portfolio
 // @Accept json
 // @Param id path int true "Technology ID"
 // @Produce json
 // @Success 200 {object} map[string]any "Message"
-// @Failure 400 {object} error "Bad request"
-// @Failure 404 {object} error "Technology not found"
-// @Failure 500 {object} error "Internal error"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Technology not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /portfolio/techs/{id} [delete]
 func (pc *PortfolioController) DeleteTechnology(c *gin.Context) {
-	technologyID := c.Param("id")
-
-	technologyID64, err := strconv.ParseInt(technologyID, 10, 64)
-	if err != nil {
-		c.JSON(400, gin.H{"error": "LanguageID is not integer"})
+	technologyID, ok := parseID(c, "Technology ID")
+	if !ok {
 		return
 	}
 
-	err = pc.service.DeleteTechnology(pc.ctx, technologyID64)
+	err := pc.service.DeleteTechnology(c.Request.Context(), technologyID)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to delete language"})
+		problem.Error(c, err)
 		return
 	}
 
-	c.JSON(200, gin.H{"message": "Language deleted successfully"})
+	c.JSON(200, gin.H{"message": "Technology deleted successfully"})
 }
 
 // @Summary Delete Project
-// @Description Delete project
+// @Description Move project to the trash together with its assets
 // @Tags Portfolio
 // @Accept json
 // @Param id path int true "Project ID"
 // @Produce json
 // @Success 200 {object} map[string]any "Message"
-// @Failure 400 {object} error "Bad request"
-// @Failure 500 {object} error "Internal error"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Project not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /portfolio/projects/{id} [delete]
 func (pc *PortfolioController) DeleteProject(c *gin.Context) {
-	projectID := c.Param("id")
-
-	projectID64, err := strconv.ParseInt(projectID, 10, 64)
-	if err != nil {
-		c.JSON(400, gin.H{"error": "ProjectID is not integer"})
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
 		return
 	}
 
-	err = pc.service.DeleteProject(pc.ctx, projectID64)
+	err := pc.service.DeleteProject(c.Request.Context(), projectID)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to delete project"})
+		problem.Error(c, err)
 		return
 	}
 
@@ -292,28 +411,31 @@ func (pc *PortfolioController) DeleteProject(c *gin.Context) {
 // @Param svg body string flase "Technology svg"
 // @Produce json
 // @Success 200 {object} map[string]any "Message"
-// @Failure 400 {object} error "Bad request"
-// @Failure 404 {object} error "Technology not found"
-// @Failure 500 {object} error "Internal error"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Technology not found"
+// @Failure 422 {object} problem.Problem "Invalid fields"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /portfolio/techs/{id} [patch]
 func (pc *PortfolioController) PatchTechnology(c *gin.Context) {
-	technologyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid technologyID"})
+	technologyID, ok := parseID(c, "Technology ID")
+	if !ok {
 		return
 	}
 
 	var technologyUpdate models.Technology
 
-	if err := c.ShouldBindJSON(&technologyUpdate); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request body"})
+	if !bindJSON(c, &technologyUpdate) {
 		return
 	}
 	technologyUpdate.ID = technologyID
-	err = pc.service.PatchTechnology(pc.ctx, &technologyUpdate)
+	err := pc.service.PatchTechnology(c.Request.Context(), &technologyUpdate)
 
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to update technology"})
+		problem.Error(c, err)
 		return
 	}
 	c.JSON(200, gin.H{"message": "Technology updated successfully"})
@@ -325,49 +447,175 @@ func (pc *PortfolioController) PatchTechnology(c *gin.Context) {
 // @Accept json
 // @Param id path int true "Project ID"
 // @Param title body string false "Project title"
-// @Param version body string false "Project version"
+// @Param version body string false "Released version, added to the releases; the project version stays at the latest release"
 // @Param description body string false "Project description"
-// @Param language_id body int false "Language ID"
+// @Param tech_id body []int64 false "Technology ID"
 // @Param isActive body bool false "Is active"
 // @Param isArchived body bool false "Is archived"
 // @Param isDeveloping body bool false "Is developing"
 // @Param links body string false "Links"
 // @Produce json
 // @Success 200 {object} map[string]any "Message"
-// @Failure 400 {object} error "Bad request"
-// @Failure 404 {object} error "Technology not found"
-// @Failure 500 {object} error "Internal error"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Technology not found"
+// @Failure 422 {object} problem.Problem "Invalid fields"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /portfolio/projects/{id} [patch]
 func (pc *PortfolioController) PatchProject(c *gin.Context) {
 
-	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+
+	var projectUpdate models.Project
+
+	if !bindJSON(c, &projectUpdate) {
+		return
+	}
+
+	err := pc.service.PatchProject(c.Request.Context(), projectID, &projectUpdate)
+
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid LanguageID"})
+		problem.Error(c, err)
 		return
 	}
+	c.JSON(200, gin.H{"message": "Project updated successfully"})
+}
 
-	project, err := pc.service.GetProject(pc.ctx, projectID)
+// @Summary Technology trash
+// @Description Get the technologies in the trash
+// @Tags Portfolio
+// @Param tech_id query []int64 false "Technology ID"
+// @Param sort query string false "Comma separated sort fields, prefix with - for descending"
+// @Param limit query int false "Limit of technologies"
+// @Param offset query int false "Offset of technologies"
+// @Param cursor query string false "Cursor of the next page from the Link header"
+// @Produce json
+// @Success 200 {array} models.Technology "Technology"
+// @Header 200 {integer} X-Total-Count "Total number of technologies"
+// @Header 200 {string} Link "RFC 8288 links to first, prev, next and last pages"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Admin role required"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Router /portfolio/trash/techs [get]
+func (pc *PortfolioController) GetListTrashTechnologies(c *gin.Context) {
+	filter := &models.TechnologyFilter{}
+
+	if !bindQuery(c, filter) {
+		return
+	}
+	filter.Deleted = true
+
+	technologies, pageInfo, err := pc.service.ListTechnologies(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to get language"})
+		problem.Error(c, err)
 		return
 	}
 
-	if project == nil {
-		c.JSON(404, gin.H{"error": "Language with id " + c.Param("id") + " not found"})
+	setPageHeaders(c, pageInfo)
+	c.JSON(200, technologies)
+}
+
+// @Summary Project trash
+// @Description Get the projects in the trash
+// @Tags Portfolio
+// @Param q query string false "Full-text search over title, description and technology names"
+// @Param tech_id query []int64 false "Technology ID"
+// @Param sort query string false "Comma separated sort fields, prefix with - for descending"
+// @Param limit query int false "Limit of projects"
+// @Param offset query int false "Offset of projects"
+// @Param cursor query string false "Cursor of the next page from the Link header"
+// @Produce json
+// @Success 200 {array} models.Project "Project"
+// @Header 200 {integer} X-Total-Count "Total number of projects"
+// @Header 200 {string} Link "RFC 8288 links to first, prev, next and last pages"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Admin role required"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Router /portfolio/trash/projects [get]
+func (pc *PortfolioController) GetListTrashProjects(c *gin.Context) {
+	filter := &models.ProjectFilter{}
+
+	if !bindQuery(c, filter) {
 		return
 	}
-	var projectUpdate models.Project
+	filter.Deleted = true
 
-	if err := c.ShouldBindJSON(&projectUpdate); err != nil {
-		c.JSON(400, gin.H{"error": "Invalid request body"})
+	projects, pageInfo, err := pc.service.ListProjects(c.Request.Context(), filter)
+	if err != nil {
+		problem.Error(c, err)
 		return
 	}
 
-	err = pc.service.PatchProject(pc.ctx, project, &projectUpdate)
+	setPageHeaders(c, pageInfo)
+	c.JSON(200, projects)
+}
 
+// @Summary Restore Technology
+// @Description Take technology out of the trash together with its links to projects
+// @Tags Portfolio
+// @Param id path int true "Technology ID"
+// @Produce json
+// @Success 200 {object} models.Technology "Restored technology"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Technology not found in the trash"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/techs/{id}/restore [post]
+func (pc *PortfolioController) RestoreTechnology(c *gin.Context) {
+	technologyID, ok := parseID(c, "Technology ID")
+	if !ok {
+		return
+	}
+
+	technology, err := pc.service.RestoreTechnology(c.Request.Context(), technologyID)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to update project"})
+		problem.Error(c, err)
 		return
 	}
-	c.JSON(200, gin.H{"message": "Project updated successfully"})
+
+	c.JSON(200, technology)
+}
+
+// @Summary Restore Project
+// @Description Take project out of the trash together with its technology links and assets
+// @Tags Portfolio
+// @Param id path int true "Project ID"
+// @Produce json
+// @Success 200 {object} models.Project "Restored project"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Project not found in the trash"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/projects/{id}/restore [post]
+func (pc *PortfolioController) RestoreProject(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+
+	project, err := pc.service.RestoreProject(c.Request.Context(), projectID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, project)
 }
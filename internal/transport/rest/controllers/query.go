@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"gowebsite/internal/transport/rest/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindQuery binds the query string into filter, a pointer to a struct with
+// form tags. Parameters the filter does not declare are ignored; the sort
+// fields are checked against the sortable columns by the repository. It
+// writes a 400 problem and returns false on failure.
+func bindQuery(c *gin.Context, filter any) bool {
+	if err := c.ShouldBindQuery(filter); err != nil {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_query", err.Error()))
+		return false
+	}
+	return true
+}
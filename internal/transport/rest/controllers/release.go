@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"gowebsite/internal/models"
+	"gowebsite/internal/transport/rest/problem"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseReleaseID reads the :releaseId path parameter like parseID.
+func parseReleaseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("releaseId"), 10, 64)
+	if err != nil {
+		problem.Abort(c, problem.New(http.StatusBadRequest, "invalid_id", "Release ID is not integer"))
+		return 0, false
+	}
+	return id, true
+}
+
+// @Summary Project release list
+// @Description Get the releases of a project, latest version first unless sorted otherwise. Versions are compared by semver precedence
+// @Tags Portfolio
+// @Param id path int true "Project ID"
+// @Param min_version query string false "Lowest version listed"
+// @Param max_version query string false "Highest version listed"
+// @Param prerelease query bool false "Only pre-releases when true, only releases when false"
+// @Param sort query string false "Comma separated sort fields, prefix with - for descending"
+// @Param limit query int false "Limit of releases"
+// @Param offset query int false "Offset of releases"
+// @Param cursor query string false "Cursor of the next page from the Link header"
+// @Produce json
+// @Success 200 {array} models.Release "Releases"
+// @Header 200 {integer} X-Total-Count "Total number of releases"
+// @Header 200 {string} Link "RFC 8288 links to first, prev, next and last pages"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Project not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Router /portfolio/projects/{id}/releases [get]
+func (pc *PortfolioController) GetListReleases(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+
+	filter := &models.ReleaseFilter{}
+	if !bindQuery(c, filter) {
+		return
+	}
+
+	releases, pageInfo, err := pc.service.ListReleases(c.Request.Context(), projectID, filter)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	setPageHeaders(c, pageInfo)
+	c.JSON(200, releases)
+}
+
+// @Summary Project release
+// @Description Get a release of a project
+// @Tags Portfolio
+// @Param id path int true "Project ID"
+// @Param releaseId path int true "Release ID"
+// @Produce json
+// @Success 200 {object} models.Release "Release"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 404 {object} problem.Problem "Release not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Router /portfolio/projects/{id}/releases/{releaseId} [get]
+func (pc *PortfolioController) GetRelease(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+	releaseID, ok := parseReleaseID(c)
+	if !ok {
+		return
+	}
+
+	release, err := pc.service.GetRelease(c.Request.Context(), projectID, releaseID)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, release)
+}
+
+// @Summary Create project release
+// @Description Add a release to a project. The latest release by semver precedence is the version of the project
+// @Tags Portfolio
+// @Accept json
+// @Param id path int true "Project ID"
+// @Param release body models.Release true "Release"
+// @Produce json
+// @Success 201 {object} models.Release "Release"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Failure 404 {object} problem.Problem "Project not found"
+// @Failure 409 {object} problem.Problem "Version already released"
+// @Failure 422 {object} problem.Problem "Invalid fields"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/projects/{id}/releases [post]
+func (pc *PortfolioController) CreateRelease(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+
+	var release models.Release
+	if !bindJSON(c, &release) {
+		return
+	}
+
+	created, err := pc.service.CreateRelease(c.Request.Context(), projectID, &release)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(201, created)
+}
+
+// @Summary Update project release
+// @Description Change a release of a project
+// @Tags Portfolio
+// @Accept json
+// @Param id path int true "Project ID"
+// @Param releaseId path int true "Release ID"
+// @Param release body models.ReleaseUpdate true "Release update"
+// @Produce json
+// @Success 200 {object} models.Release "Release"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Failure 404 {object} problem.Problem "Release not found"
+// @Failure 409 {object} problem.Problem "Version already released"
+// @Failure 422 {object} problem.Problem "Invalid fields"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/projects/{id}/releases/{releaseId} [patch]
+func (pc *PortfolioController) PatchRelease(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+	releaseID, ok := parseReleaseID(c)
+	if !ok {
+		return
+	}
+
+	var update models.ReleaseUpdate
+	if !bindJSON(c, &update) {
+		return
+	}
+
+	release, err := pc.service.PatchRelease(c.Request.Context(), projectID, releaseID, &update)
+	if err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, release)
+}
+
+// @Summary Delete project release
+// @Description Delete a release of a project. The project keeps its version when the last release is deleted
+// @Tags Portfolio
+// @Param id path int true "Project ID"
+// @Param releaseId path int true "Release ID"
+// @Produce json
+// @Success 200 {object} map[string]any "Message"
+// @Failure 400 {object} problem.Problem "Bad request"
+// @Failure 401 {object} problem.Problem "Authentication required"
+// @Failure 403 {object} problem.Problem "Missing scope"
+// @Failure 404 {object} problem.Problem "Release not found"
+// @Failure 500 {object} problem.Problem "Internal error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /portfolio/projects/{id}/releases/{releaseId} [delete]
+func (pc *PortfolioController) DeleteRelease(c *gin.Context) {
+	projectID, ok := parseID(c, "Project ID")
+	if !ok {
+		return
+	}
+	releaseID, ok := parseReleaseID(c)
+	if !ok {
+		return
+	}
+
+	if err := pc.service.DeleteRelease(c.Request.Context(), projectID, releaseID); err != nil {
+		problem.Error(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Release deleted successfully"})
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/internal/site"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// staticMaxAge is long because static files have their content hash in
+// their name: a changed file gets a new URL.
+const staticMaxAge = 365 * 24 * time.Hour
+
+type SiteService interface {
+	GetTechnology(ctx context.Context, id int64) (*models.Technology, error)
+	ListTechnologies(ctx context.Context, filter *models.TechnologyFilter) ([]*models.Technology, *models.PageInfo, error)
+	GetProjectBySlug(ctx context.Context, slug string) (*models.Project, error)
+	ListProjects(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, *models.PageInfo, error)
+}
+
+// SiteController serves the HTML pages of the portfolio, rendered with the
+// theme of site.SiteConfig.
+type SiteController struct {
+	service  SiteService
+	theme    *site.Theme
+	renderer *site.Renderer
+	title    string
+}
+
+func NewSiteController(service SiteService, theme *site.Theme, renderer *site.Renderer, title string) *SiteController {
+	return &SiteController{service: service, theme: theme, renderer: renderer, title: title}
+}
+
+func (sc *SiteController) Index(c *gin.Context) {
+	projects, _, err := sc.service.ListProjects(c.Request.Context(), &models.ProjectFilter{})
+	if err != nil {
+		sc.error(c, err)
+		return
+	}
+	sc.render(c, site.PageIndex, &site.Page{Projects: projects})
+}
+
+func (sc *SiteController) Project(c *gin.Context) {
+	project, err := sc.service.GetProjectBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		sc.error(c, err)
+		return
+	}
+	sc.render(c, site.PageProject, &site.Page{Title: project.Title, Project: project})
+}
+
+func (sc *SiteController) Technologies(c *gin.Context) {
+	technologies, _, err := sc.service.ListTechnologies(c.Request.Context(), &models.TechnologyFilter{})
+	if err != nil {
+		sc.error(c, err)
+		return
+	}
+	projects, _, err := sc.service.ListProjects(c.Request.Context(), &models.ProjectFilter{})
+	if err != nil {
+		sc.error(c, err)
+		return
+	}
+	sc.render(c, site.PageTechnologies, &site.Page{Title: "Technologies", Technologies: site.Entries(technologies, projects)})
+}
+
+func (sc *SiteController) Technology(c *gin.Context) {
+	technologyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		sc.notFound(c)
+		return
+	}
+	technology, err := sc.service.GetTechnology(c.Request.Context(), technologyID)
+	if err != nil {
+		sc.error(c, err)
+		return
+	}
+	projects, _, err := sc.service.ListProjects(c.Request.Context(), &models.ProjectFilter{TechnologiesID: &[]int64{technologyID}})
+	if err != nil {
+		sc.error(c, err)
+		return
+	}
+	entry := &site.TechnologyEntry{Technology: technology, Projects: projects}
+	sc.render(c, site.PageTechnology, &site.Page{Title: technology.Name, Technology: entry})
+}
+
+// Static serves the static files of the theme by their hashed names.
+func (sc *SiteController) Static(c *gin.Context) {
+	name := "static" + c.Param("filepath")
+	data, err := sc.theme.ReadStatic(name)
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	c.Header("Cache-Control", publicMaxAge(staticMaxAge)+", immutable")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// render writes page with an ETag, so revisits only cost a 304.
+func (sc *SiteController) render(c *gin.Context, page string, data *site.Page) {
+	data.SiteTitle = sc.title
+	var buf bytes.Buffer
+	if err := sc.renderer.Render(&buf, page, data); err != nil {
+		sc.error(c, err)
+		return
+	}
+	conditional(c, revalidate, time.Time{}, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// error answers with the not found page for missing records and a plain
+// 500 otherwise; pages are for browsers, not API clients.
+func (sc *SiteController) error(c *gin.Context, err error) {
+	if errors.Is(err, apperrors.ErrNotFound) {
+		sc.notFound(c)
+		return
+	}
+	_ = c.Error(err)
+	c.String(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+}
+
+func (sc *SiteController) notFound(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := sc.renderer.Render(&buf, site.PageNotFound, &site.Page{SiteTitle: sc.title, Title: "Not found"}); err != nil {
+		_ = c.Error(err)
+		c.String(http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+	c.Data(http.StatusNotFound, "text/html; charset=utf-8", buf.Bytes())
+}
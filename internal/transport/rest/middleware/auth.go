@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/internal/transport/rest/problem"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TokenParser interface {
+	ParseAccessToken(token string) (*models.Principal, error)
+}
+
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.Principal, *models.QuotaStatus, error)
+}
+
+// Authenticate resolves the credentials of the request, if any, and stores
+// the caller in the request context. It accepts "Bearer <jwt>" for users and
+// "ApiKey <key>" for automation clients. Requests without credentials
+// continue anonymously; RequireRole and RequireScope decide whether a route
+// needs a caller. Invalid credentials are rejected right away instead of
+// being downgraded.
+func Authenticate(tokens TokenParser, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		scheme, credentials, _ := strings.Cut(header, " ")
+		credentials = strings.TrimSpace(credentials)
+
+		var principal *models.Principal
+		var err error
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			principal, err = tokens.ParseAccessToken(credentials)
+		case strings.EqualFold(scheme, "ApiKey"):
+			var quota *models.QuotaStatus
+			principal, quota, err = apiKeys.AuthenticateAPIKey(c.Request.Context(), credentials)
+			setQuotaHeaders(c, quota)
+			if errors.Is(err, apperrors.ErrRateLimited) && quota != nil {
+				c.Header("Retry-After", strconv.Itoa(int(time.Until(quota.Reset).Seconds())+1))
+				problem.Error(c, err)
+				return
+			}
+		default:
+			err = apperrors.Unauthorized("unsupported_auth_scheme", "Unsupported authorization scheme")
+		}
+		if err != nil {
+			abortUnauthorized(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(models.ContextWithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// RequireRole lets through only users with the given role. API keys never
+// have a role, so routes guarded by it are for humans only.
+func RequireRole(role models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := models.PrincipalFromContext(c.Request.Context())
+		if principal == nil {
+			abortUnauthorized(c, apperrors.Unauthorized("authentication_required", "Authentication is required"))
+			return
+		}
+		if principal.Role != role {
+			problem.Error(c, apperrors.Forbidden("insufficient_role", "This action requires the %s role", role))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope lets through only callers that have scope.
+func RequireScope(scope models.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := models.PrincipalFromContext(c.Request.Context())
+		if principal == nil {
+			abortUnauthorized(c, apperrors.Unauthorized("authentication_required", "Authentication is required"))
+			return
+		}
+		if !principal.Can(scope) {
+			problem.Error(c, apperrors.Forbidden("insufficient_scope", "This action requires the %s scope", scope))
+			return
+		}
+		c.Next()
+	}
+}
+
+// CheckScope is RequireScope for public routes: anonymous callers are let
+// through, authenticated ones still need scope.
+func CheckScope(scope models.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := models.PrincipalFromContext(c.Request.Context())
+		if principal != nil && !principal.Can(scope) {
+			problem.Error(c, apperrors.Forbidden("insufficient_scope", "This action requires the %s scope", scope))
+			return
+		}
+		c.Next()
+	}
+}
+
+func setQuotaHeaders(c *gin.Context, quota *models.QuotaStatus) {
+	if quota == nil {
+		return
+	}
+	c.Header("X-RateLimit-Limit", strconv.Itoa(quota.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(quota.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(quota.Reset.Unix(), 10))
+}
+
+func abortUnauthorized(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", `Bearer realm="gowebsite", ApiKey realm="gowebsite"`)
+	problem.Error(c, err)
+}
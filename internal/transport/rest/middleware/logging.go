@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"fmt"
+	"gowebsite/internal/transport/rest/problem"
+	"gowebsite/pkg/logger"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AccessLog writes one zap entry per request once the handler chain is done.
+// It must be registered after RequestID so the request-scoped logger is set.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		query := c.Request.URL.RawQuery
+
+		c.Next()
+
+		ctx := c.Request.Context()
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.String("user_agent", c.Request.UserAgent()),
+			zap.Int("bytes", c.Writer.Size()),
+		}
+		if query != "" {
+			fields = append(fields, zap.String("query", query))
+		}
+		if route := c.FullPath(); route != "" {
+			fields = append(fields, zap.String("route", route))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		l := logger.GetLoggerFromCtx(ctx)
+		switch {
+		case status >= http.StatusInternalServerError:
+			l.Error(ctx, "Request completed", fields...)
+		case status >= http.StatusBadRequest:
+			l.Warn(ctx, "Request completed", fields...)
+		default:
+			l.Info(ctx, "Request completed", fields...)
+		}
+	}
+}
+
+// Recovery turns a panic in a handler into a 500 problem and logs it with
+// the stack trace through the request-scoped logger.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if p := recover(); p != nil {
+				ctx := c.Request.Context()
+				logger.GetLoggerFromCtx(ctx).Error(ctx, "Panic recovered",
+					zap.String("panic", fmt.Sprint(p)),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				problem.Abort(c, problem.New(http.StatusInternalServerError, "internal_error", "Internal server error"))
+			}
+		}()
+		c.Next()
+	}
+}
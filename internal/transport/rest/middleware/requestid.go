@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"gowebsite/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps client supplied IDs so they can't bloat log lines.
+const maxRequestIDLength = 128
+
+// RequestID accepts the X-Request-ID header sent by the client or generates
+// a new one, stores it together with a request-scoped logger in the request
+// context and echoes it back in the response.
+func RequestID(base logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		requestLogger := base.With(
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+		)
+
+		ctx := context.WithValue(c.Request.Context(), logger.RequestID, requestID)
+		ctx = context.WithValue(ctx, logger.LoggerKey, requestLogger)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Set(string(logger.RequestID), requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// isValidRequestID allows only printable ASCII without spaces, which keeps
+// header and log injection out of the echoed value.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
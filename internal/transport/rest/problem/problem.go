@@ -0,0 +1,111 @@
+// Package problem renders errors as RFC 7807 application/problem+json
+// responses.
+package problem
+
+import (
+	"context"
+	"errors"
+	"gowebsite/internal/apperrors"
+	"gowebsite/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const ContentType = "application/problem+json"
+
+// statusClientClosedRequest is the de facto status for requests the client
+// abandoned before a response was written.
+const statusClientClosedRequest = 499
+
+// Problem is an RFC 7807 problem details object. Code is a stable machine
+// readable identifier clients can switch on.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors lists every invalid field of a validation problem.
+	Errors []apperrors.FieldError `json:"errors,omitempty"`
+}
+
+// New builds a problem for the given status and code.
+func New(status int, code, detail string) *Problem {
+	return &Problem{
+		Type:   "urn:gowebsite:problem:" + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Abort writes p and stops the handler chain.
+func Abort(c *gin.Context, p *Problem) {
+	p.Instance = c.Request.URL.Path
+	if requestID, ok := c.Get(string(logger.RequestID)); ok {
+		p.RequestID, _ = requestID.(string)
+	}
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// Error maps err to a problem and writes it. Unknown errors become a 500
+// whose detail does not leak internals; they are logged instead.
+func Error(c *gin.Context, err error) {
+	_ = c.Error(err)
+	Abort(c, FromError(c.Request.Context(), err))
+}
+
+// FromError maps domain errors to problems.
+func FromError(ctx context.Context, err error) *Problem {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return New(statusClientClosedRequest, "client_closed_request", "Request was canceled by the client")
+	}
+	if appErr, ok := apperrors.As(err); ok {
+		p := New(statusOf(appErr.Kind), appErr.Code, appErr.Message)
+		p.Errors = appErr.Fields
+		return p
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return New(http.StatusGatewayTimeout, "timeout", "Request timed out")
+	case errors.Is(err, context.Canceled):
+		return New(statusClientClosedRequest, "client_closed_request", "Request was canceled by the client")
+	}
+
+	logger.GetLoggerFromCtx(ctx).Error(ctx, "Unhandled error", zap.Error(err))
+	return New(http.StatusInternalServerError, "internal_error", "Internal server error")
+}
+
+func statusOf(kind error) int {
+	switch kind {
+	case apperrors.ErrNotFound:
+		return http.StatusNotFound
+	case apperrors.ErrConflict:
+		return http.StatusConflict
+	case apperrors.ErrValidation, apperrors.ErrForeignKey:
+		return http.StatusUnprocessableEntity
+	case apperrors.ErrBadRequest:
+		return http.StatusBadRequest
+	case apperrors.ErrTimeout:
+		return http.StatusGatewayTimeout
+	case apperrors.ErrTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case apperrors.ErrMediaType:
+		return http.StatusUnsupportedMediaType
+	case apperrors.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case apperrors.ErrForbidden:
+		return http.StatusForbidden
+	case apperrors.ErrRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"context"
+	"gowebsite/internal/config"
+	"gowebsite/internal/models"
+	"gowebsite/internal/transport/rest/controllers"
+	"gowebsite/internal/transport/rest/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AuthRoutes(ctx context.Context, r *gin.RouterGroup, authService controllers.AuthService, apiKeyService controllers.APIKeyService, cfg *config.Config) {
+	authController := controllers.NewAuthController(authService)
+	apiKeyController := controllers.NewAPIKeyController(apiKeyService)
+
+	readTimeout := middleware.Timeout(cfg.RESTReadTimeout)
+	writeTimeout := middleware.Timeout(cfg.RESTWriteTimeout)
+	requireAdmin := middleware.RequireRole(models.RoleAdmin)
+
+	authGroup := r.Group("/auth")
+	{
+		authGroup.POST("/login", writeTimeout, authController.Login)
+		authGroup.POST("/refresh", writeTimeout, authController.Refresh)
+		authGroup.POST("/logout", writeTimeout, authController.Logout)
+
+		authGroup.GET("/api-keys", readTimeout, requireAdmin, apiKeyController.GetListAPIKeys)
+		authGroup.POST("/api-keys", writeTimeout, requireAdmin, apiKeyController.CreateAPIKey)
+		authGroup.DELETE("/api-keys/:id", writeTimeout, requireAdmin, apiKeyController.RevokeAPIKey)
+	}
+}
@@ -2,33 +2,77 @@ package routes
 
 import (
 	"context"
+	"gowebsite/internal/config"
+	"gowebsite/internal/models"
 	"gowebsite/internal/repository"
 	"gowebsite/internal/service"
 	"gowebsite/internal/transport/rest/controllers"
+	"gowebsite/internal/transport/rest/middleware"
 	"gowebsite/pkg/db/postgres"
+	"gowebsite/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
-func PortfolioRoutes(ctx context.Context, r *gin.RouterGroup, db *postgres.DB) {
+func PortfolioRoutes(ctx context.Context, r *gin.RouterGroup, db *postgres.DB, store storage.Storage, cfg *config.Config) {
 	portfolioRepo := repository.NewPortfolioRepository(db)
-	portfolioService := service.NewPortfolioService(portfolioRepo)
-	portfolioController := controllers.NewPortfolioController(ctx, portfolioService)
+	portfolioService := service.NewPortfolioService(portfolioRepo, store, cfg.AssetConfig)
+	portfolioController := controllers.NewPortfolioController(portfolioService)
+
+	readTimeout := middleware.Timeout(cfg.RESTReadTimeout)
+	writeTimeout := middleware.Timeout(cfg.RESTWriteTimeout)
+
+	techsRead := middleware.CheckScope(models.ScopeTechsRead)
+	techsWrite := middleware.RequireScope(models.ScopeTechsWrite)
+	projectsRead := middleware.CheckScope(models.ScopeProjectsRead)
+	projectsWrite := middleware.RequireScope(models.ScopeProjectsWrite)
+	requireAdmin := middleware.RequireRole(models.RoleAdmin)
+
 	portfolioGroup := r.Group("/portfolio")
 	{
-		portfolioGroup.GET("/techs", portfolioController.GetListTechnologies)
-		portfolioGroup.GET("/projects", portfolioController.GetListProjects)
+		portfolioGroup.GET("/techs", readTimeout, techsRead, portfolioController.GetListTechnologies)
+		portfolioGroup.GET("/projects", readTimeout, projectsRead, portfolioController.GetListProjects)
+
+		portfolioGroup.GET("/techs/stats", readTimeout, techsRead, portfolioController.GetTechnologyStats)
+		portfolioGroup.GET("/techs/:id", readTimeout, techsRead, portfolioController.GetTechnology)
+		portfolioGroup.GET("/techs/:id/icon.svg", readTimeout, techsRead, portfolioController.GetTechnologyIcon)
+		portfolioGroup.GET("/projects/:id", readTimeout, projectsRead, portfolioController.GetProject)
+
+		portfolioGroup.POST("/techs", writeTimeout, techsWrite, portfolioController.CreateTechnology)
+		portfolioGroup.POST("/projects", writeTimeout, projectsWrite, portfolioController.CreateProject)
+
+		portfolioGroup.DELETE("/techs/:id", writeTimeout, techsWrite, portfolioController.DeleteTechnology)
+		portfolioGroup.DELETE("/projects/:id", writeTimeout, projectsWrite, portfolioController.DeleteProject)
+
+		portfolioGroup.POST("/techs/:id/restore", writeTimeout, techsWrite, portfolioController.RestoreTechnology)
+		portfolioGroup.POST("/projects/:id/restore", writeTimeout, projectsWrite, portfolioController.RestoreProject)
+
+		portfolioGroup.GET("/projects/:id/releases", readTimeout, projectsRead, portfolioController.GetListReleases)
+		portfolioGroup.GET("/projects/:id/releases/:releaseId", readTimeout, projectsRead, portfolioController.GetRelease)
+		portfolioGroup.POST("/projects/:id/releases", writeTimeout, projectsWrite, portfolioController.CreateRelease)
+		portfolioGroup.PATCH("/projects/:id/releases/:releaseId", writeTimeout, projectsWrite, portfolioController.PatchRelease)
+		portfolioGroup.DELETE("/projects/:id/releases/:releaseId", writeTimeout, projectsWrite, portfolioController.DeleteRelease)
+
+		// History shows who made each change, so it is for writers only.
+		portfolioGroup.GET("/techs/:id/history", readTimeout, techsWrite, portfolioController.GetTechnologyHistory)
+		portfolioGroup.GET("/projects/:id/history", readTimeout, projectsWrite, portfolioController.GetProjectHistory)
+		portfolioGroup.POST("/techs/:id/history/:revisionId/revert", writeTimeout, techsWrite, portfolioController.RevertTechnology)
+		portfolioGroup.POST("/projects/:id/history/:revisionId/revert", writeTimeout, projectsWrite, portfolioController.RevertProject)
 
-		portfolioGroup.GET("/techs/:id", portfolioController.GetTechnology)
-		portfolioGroup.GET("/projects/:id", portfolioController.GetProject)
+		portfolioGroup.GET("/export", readTimeout, techsRead, projectsRead, portfolioController.ExportBundle)
 
-		portfolioGroup.POST("/techs", portfolioController.CreateTechnology)
-		portfolioGroup.POST("/projects", portfolioController.CreateProject)
+		portfolioGroup.GET("/trash/techs", readTimeout, requireAdmin, portfolioController.GetListTrashTechnologies)
+		portfolioGroup.GET("/trash/projects", readTimeout, requireAdmin, portfolioController.GetListTrashProjects)
 
-		portfolioGroup.DELETE("/techs/:id", portfolioController.DeleteTechnology)
-		portfolioGroup.DELETE("/projects/:id", portfolioController.DeleteProject)
+		portfolioGroup.PATCH("/techs/:id", writeTimeout, techsWrite, portfolioController.PatchTechnology)
+		portfolioGroup.PATCH("/projects/:id", writeTimeout, projectsWrite, portfolioController.PatchProject)
 
-		portfolioGroup.PATCH("/techs/:id", portfolioController.PatchTechnology)
-		portfolioGroup.PATCH("/projects/:id", portfolioController.PatchProject)
+		// Uploads get the limit of the asset itself plus room for the rest
+		// of the multipart body.
+		uploadLimit := middleware.LimitBody(cfg.MaxAssetSize + 1<<20)
+		portfolioGroup.GET("/projects/:id/assets", readTimeout, projectsRead, portfolioController.GetListAssets)
+		portfolioGroup.POST("/projects/:id/assets", writeTimeout, projectsWrite, uploadLimit, portfolioController.UploadAsset)
+		portfolioGroup.PATCH("/projects/:id/assets/:assetId", writeTimeout, projectsWrite, portfolioController.PatchAsset)
+		portfolioGroup.DELETE("/projects/:id/assets/:assetId", writeTimeout, projectsWrite, portfolioController.DeleteAsset)
 	}
 }
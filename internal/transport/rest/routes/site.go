@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"gowebsite/internal/config"
+	"gowebsite/internal/models"
+	"gowebsite/internal/repository"
+	"gowebsite/internal/service"
+	"gowebsite/internal/site"
+	"gowebsite/internal/transport/rest/controllers"
+	"gowebsite/internal/transport/rest/middleware"
+	"gowebsite/pkg/db/postgres"
+	"gowebsite/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SiteRoutes serves the HTML pages and the feeds of the portfolio next to
+// the API.
+// apiPath is where the API is mounted, for the icon endpoint.
+func SiteRoutes(ctx context.Context, r gin.IRouter, db *postgres.DB, store storage.Storage, cfg *config.Config, apiPath string) error {
+	theme, err := site.LoadTheme(cfg.ThemeDir)
+	if err != nil {
+		return err
+	}
+	renderer, err := site.NewRenderer(theme, site.Links{
+		BasePath: "/",
+		IconURL: func(technology *models.Technology) string {
+			if !technology.Svg.Valid || technology.Svg.String == "" {
+				return ""
+			}
+			return fmt.Sprintf("%s/portfolio/techs/%d/icon.svg", apiPath, technology.ID)
+		},
+		AssetURL: func(asset *models.ProjectAsset) string {
+			return asset.URL
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	portfolioService := service.NewPortfolioService(repository.NewPortfolioRepository(db), store, cfg.AssetConfig)
+	siteController := controllers.NewSiteController(portfolioService, theme, renderer, cfg.Title)
+	feedController := controllers.NewFeedController(portfolioService, cfg.SiteConfig)
+
+	readTimeout := middleware.Timeout(cfg.RESTReadTimeout)
+
+	r.GET("/", readTimeout, siteController.Index)
+	r.GET("/projects/:slug", readTimeout, siteController.Project)
+	r.GET("/techs", readTimeout, siteController.Technologies)
+	r.GET("/techs/:id", readTimeout, siteController.Technology)
+	r.GET("/static/*filepath", siteController.Static)
+	for _, format := range site.FeedFormats {
+		r.GET("/"+site.FeedPath(format), readTimeout, feedController.Projects(format))
+	}
+	return nil
+}
@@ -3,8 +3,15 @@ package rest
 import (
 	"context"
 	"gowebsite/docs"
+	"gowebsite/internal/config"
+	"gowebsite/internal/repository"
+	"gowebsite/internal/service"
+	"gowebsite/internal/transport/rest/middleware"
 	"gowebsite/internal/transport/rest/routes"
 	"gowebsite/pkg/db/postgres"
+	"gowebsite/pkg/logger"
+	"gowebsite/pkg/storage"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -16,8 +23,14 @@ type RESTServer struct {
 	port string
 }
 
-func NewRESTServer(ctx context.Context, db *postgres.DB, port, host string) *RESTServer {
-	r := gin.Default()
+func NewRESTServer(ctx context.Context, db *postgres.DB, store storage.Storage, cfg *config.Config) (*RESTServer, error) {
+	port, host := cfg.RESTServerPort, cfg.RESTServerHost
+	r := gin.New()
+	r.Use(
+		middleware.RequestID(logger.GetLoggerFromCtx(ctx)),
+		middleware.AccessLog(),
+		middleware.Recovery(),
+	)
 
 	r.SetTrustedProxies([]string{"127.0.0.1", host})
 	docs.SwaggerInfo.BasePath = "/api/v1"
@@ -28,14 +41,25 @@ func NewRESTServer(ctx context.Context, db *postgres.DB, port, host string) *RES
 	docs.SwaggerInfo.Schemes = []string{"http", "https"}
 
 	r.GET("/ping", func(c *gin.Context) { c.String(200, "pong") })
+	if local, ok := store.(*storage.Local); ok && strings.HasPrefix(cfg.StorageConfig.PublicURL, "/") {
+		r.Static(cfg.StorageConfig.PublicURL, local.Dir())
+	}
 
 	api := r.Group("/api")
 	v1 := api.Group("/v1")
+	if err := routes.SiteRoutes(ctx, r, db, store, cfg, v1.BasePath()); err != nil {
+		return nil, err
+	}
 
-	routes.PortfolioRoutes(ctx, v1, db)
+	authService := service.NewAuthService(repository.NewAuthRepository(db), cfg.AuthConfig)
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(db), cfg.APIKeyConfig)
+	v1.Use(middleware.Authenticate(authService, apiKeyService))
+
+	routes.AuthRoutes(ctx, v1, authService, apiKeyService, cfg)
+	routes.PortfolioRoutes(ctx, v1, db, store, cfg)
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	return &RESTServer{r: r, port: port}
+	return &RESTServer{r: r, port: port}, nil
 }
 
 func (s *RESTServer) Run(ctx context.Context) error {
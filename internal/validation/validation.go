@@ -0,0 +1,167 @@
+// Package validation checks models against the rules declared in their
+// validate struct tags and reports every broken rule as a field error.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"gowebsite/internal/apperrors"
+	"gowebsite/internal/models"
+	"gowebsite/pkg/svg"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/volatiletech/null/v9"
+)
+
+// CodeExclusive is the code of errors reported for fields that cannot be
+// set together.
+const CodeExclusive = "exclusive"
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(jsonName)
+	v.RegisterCustomTypeFunc(nullValue, null.String{}, null.Bool{})
+	if err := v.RegisterValidation("svg", isSVG); err != nil {
+		panic(err)
+	}
+	if err := v.RegisterValidation("slug", isSlug); err != nil {
+		panic(err)
+	}
+	v.RegisterStructValidation(projectRules, models.Project{})
+	return v
+}
+
+// Check validates s, a pointer to a struct, and returns every invalid field.
+func Check(s any) []apperrors.FieldError {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		panic(err)
+	}
+
+	fields := make([]apperrors.FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fields = append(fields, apperrors.FieldError{
+			Field:   fieldPath(fieldErr.Namespace()),
+			Code:    fieldErr.Tag(),
+			Message: message(fieldErr),
+		})
+	}
+	return fields
+}
+
+// Struct validates s and returns a validation error listing every invalid
+// field, or nil.
+func Struct(s any) error {
+	return apperrors.InvalidFields(Check(s))
+}
+
+// fieldPath drops the struct name the validator prefixes namespaces with.
+func fieldPath(namespace string) string {
+	_, path, _ := strings.Cut(namespace, ".")
+	return path
+}
+
+func message(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "max":
+		if fieldErr.Kind() == reflect.Slice {
+			return fmt.Sprintf("must have at most %s items", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at most %s characters long", fieldErr.Param())
+	case "min":
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fieldErr.Param())
+	case "unique":
+		return "must not contain duplicates"
+	case "semver":
+		return "must be a semantic version, e.g. 1.2.3"
+	case "http_url":
+		return "must be an http or https URL"
+	case "svg":
+		return "must be a well-formed SVG document"
+	case "slug":
+		return "must be lowercase letters and digits separated by single dashes"
+	case CodeExclusive:
+		return fmt.Sprintf("cannot be true together with %s", fieldErr.Param())
+	}
+	return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+}
+
+// SetFields returns the JSON names of the non-zero fields of s, a pointer to
+// a struct, i.e. the fields a patch sets.
+func SetFields(s any) map[string]bool {
+	fields := make(map[string]bool)
+	value := reflect.ValueOf(s).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsZero() {
+			fields[jsonName(value.Type().Field(i))] = true
+		}
+	}
+	return fields
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// nullValue lets rules see through null types: an unset value is nil, so
+// "required" fails and "omitempty" skips it. Bools are returned as pointers
+// so that a set false still passes "required".
+func nullValue(field reflect.Value) any {
+	switch value := field.Interface().(type) {
+	case null.String:
+		if value.Valid {
+			return value.String
+		}
+	case null.Bool:
+		if value.Valid {
+			return &value.Bool
+		}
+	}
+	return nil
+}
+
+// isSVG accepts documents svg.Sanitize can clean up.
+func isSVG(fl validator.FieldLevel) bool {
+	_, err := svg.Sanitize(fl.Field().String())
+	return err == nil
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// isSlug accepts slugs in the form models.Slugify derives them.
+func isSlug(fl validator.FieldLevel) bool {
+	return slugPattern.MatchString(fl.Field().String())
+}
+
+func projectRules(sl validator.StructLevel) {
+	project := sl.Current().Interface().(models.Project)
+	if project.IsActive.Valid && project.IsActive.Bool && project.IsArchived.Valid && project.IsArchived.Bool {
+		sl.ReportError(project.IsArchived, "isArchived", "IsArchived", CodeExclusive, "isActive")
+	}
+}
+
+// IsSemver reports whether s is a semantic version, as the "semver" rule
+// checks it.
+func IsSemver(s string) bool {
+	return validate.Var(s, "semver") == nil
+}
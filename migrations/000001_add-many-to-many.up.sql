@@ -28,9 +28,6 @@ CREATE TABLE IF NOT EXISTS public.project_tech
 );
 
 
-ALTER TABLE IF EXISTS public."languages"
-    OWNER to postgres;
-
 ALTER TABLE IF EXISTS public."projects"
     OWNER to postgres;
 ALTER TABLE IF EXISTS public."techs"
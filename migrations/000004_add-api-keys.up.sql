@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS public.api_keys
+(
+  id                 serial NOT NULL,
+  name               TEXT NOT NULL,
+  prefix             TEXT NOT NULL,
+  key_hash           TEXT NOT NULL,
+  scopes             TEXT[] NOT NULL DEFAULT '{}',
+  expires_at         TIMESTAMPTZ NULL,
+  last_used_at       TIMESTAMPTZ NULL,
+  quota              INTEGER NOT NULL DEFAULT 0,
+  quota_used         INTEGER NOT NULL DEFAULT 0,
+  quota_window_start TIMESTAMPTZ NOT NULL DEFAULT now(),
+  created_by         INTEGER NULL,
+  created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+  revoked_at         TIMESTAMPTZ NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash),
+  CONSTRAINT api_keys_quota_check CHECK (quota >= 0),
+  FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS techs_search_vector ON public.techs;
+DROP TRIGGER IF EXISTS project_tech_search_vector ON public.project_tech;
+DROP TRIGGER IF EXISTS projects_search_vector ON public.projects;
+DROP FUNCTION IF EXISTS public.techs_search_vector_trigger();
+DROP FUNCTION IF EXISTS public.project_tech_search_vector_trigger();
+DROP FUNCTION IF EXISTS public.projects_search_vector_trigger();
+DROP FUNCTION IF EXISTS public.project_search_vector(INTEGER, TEXT, TEXT);
+DROP INDEX IF EXISTS public.projects_search_vector_idx;
+ALTER TABLE public.projects DROP COLUMN IF EXISTS search_vector;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS public.portfolio;
@@ -0,0 +1,83 @@
+-- Latin words are stemmed as English, Cyrillic words as Russian, so one
+-- configuration covers our bilingual content.
+CREATE TEXT SEARCH CONFIGURATION public.portfolio (COPY = pg_catalog.russian);
+ALTER TEXT SEARCH CONFIGURATION public.portfolio
+  ALTER MAPPING FOR asciiword, asciihword, hword_asciipart WITH english_stem;
+ALTER TEXT SEARCH CONFIGURATION public.portfolio
+  ALTER MAPPING FOR word, hword, hword_part WITH russian_stem;
+
+ALTER TABLE public.projects
+  ADD COLUMN IF NOT EXISTS search_vector tsvector NOT NULL DEFAULT '';
+
+CREATE OR REPLACE FUNCTION public.project_search_vector(p_id INTEGER, p_title TEXT, p_description TEXT)
+RETURNS tsvector
+LANGUAGE sql STABLE AS $$
+  SELECT setweight(to_tsvector('public.portfolio', coalesce(p_title, '')), 'A')
+      || setweight(to_tsvector('public.portfolio', coalesce(p_description, '')), 'B')
+      || setweight(to_tsvector('public.portfolio', coalesce((
+           SELECT string_agg(t.name, ' ')
+           FROM project_tech pt
+           JOIN techs t ON t.id = pt.tech_id
+           WHERE pt.project_id = p_id
+         ), '')), 'C')
+$$;
+
+-- projects: recompute on insert and when the text changes.
+CREATE OR REPLACE FUNCTION public.projects_search_vector_trigger()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  NEW.search_vector := project_search_vector(NEW.id, NEW.title, NEW.description);
+  RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER projects_search_vector
+  BEFORE INSERT OR UPDATE OF title, description ON public.projects
+  FOR EACH ROW EXECUTE FUNCTION projects_search_vector_trigger();
+
+-- project_tech: recompute the project a technology was linked to or
+-- unlinked from.
+CREATE OR REPLACE FUNCTION public.project_tech_search_vector_trigger()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  changed_id INTEGER;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    changed_id := OLD.project_id;
+  ELSE
+    changed_id := NEW.project_id;
+  END IF;
+  UPDATE projects
+  SET search_vector = project_search_vector(id, title, description)
+  WHERE id = changed_id;
+  RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER project_tech_search_vector
+  AFTER INSERT OR DELETE ON public.project_tech
+  FOR EACH ROW EXECUTE FUNCTION project_tech_search_vector_trigger();
+
+-- techs: recompute the projects using a renamed technology.
+CREATE OR REPLACE FUNCTION public.techs_search_vector_trigger()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  UPDATE projects
+  SET search_vector = project_search_vector(id, title, description)
+  WHERE id IN (SELECT project_id FROM project_tech WHERE tech_id = NEW.id);
+  RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER techs_search_vector
+  AFTER UPDATE OF name ON public.techs
+  FOR EACH ROW EXECUTE FUNCTION techs_search_vector_trigger();
+
+UPDATE public.projects
+SET search_vector = project_search_vector(id, title, description);
+
+CREATE INDEX IF NOT EXISTS projects_search_vector_idx
+  ON public.projects USING GIN (search_vector);
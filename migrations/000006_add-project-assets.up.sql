@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS public.project_assets
+(
+  id           serial NOT NULL,
+  project_id   INTEGER NOT NULL,
+  storage_key  TEXT NOT NULL,
+  filename     TEXT NOT NULL,
+  content_type TEXT NOT NULL,
+  size         BIGINT NOT NULL,
+  caption      TEXT NOT NULL DEFAULT '',
+  position     INTEGER NOT NULL DEFAULT 0,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (id),
+  CONSTRAINT project_assets_storage_key_key UNIQUE (storage_key),
+  FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS project_assets_project_id_position_idx
+  ON public.project_assets (project_id, position);
@@ -0,0 +1,7 @@
+ALTER TABLE public.projects
+  DROP COLUMN IF EXISTS updated_at,
+  DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE public.techs
+  DROP COLUMN IF EXISTS updated_at,
+  DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,28 @@
+DROP TRIGGER IF EXISTS techs_search_vector ON public.techs;
+CREATE TRIGGER techs_search_vector
+  AFTER UPDATE OF name ON public.techs
+  FOR EACH ROW EXECUTE FUNCTION techs_search_vector_trigger();
+
+CREATE OR REPLACE FUNCTION public.project_search_vector(p_id INTEGER, p_title TEXT, p_description TEXT)
+RETURNS tsvector
+LANGUAGE sql STABLE AS $$
+  SELECT setweight(to_tsvector('public.portfolio', coalesce(p_title, '')), 'A')
+      || setweight(to_tsvector('public.portfolio', coalesce(p_description, '')), 'B')
+      || setweight(to_tsvector('public.portfolio', coalesce((
+           SELECT string_agg(t.name, ' ')
+           FROM project_tech pt
+           JOIN techs t ON t.id = pt.tech_id
+           WHERE pt.project_id = p_id
+         ), '')), 'C')
+$$;
+
+-- Without deleted_at the trash can't be told apart, so it is emptied the
+-- way deletes worked before.
+DELETE FROM public.projects WHERE deleted_at IS NOT NULL;
+DELETE FROM public.techs WHERE deleted_at IS NOT NULL;
+
+UPDATE public.projects
+SET search_vector = project_search_vector(id, title, description);
+
+ALTER TABLE public.projects DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE public.techs DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,25 @@
+ALTER TABLE public.techs
+  ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+
+ALTER TABLE public.projects
+  ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+
+-- Technologies in the trash keep their project_tech rows so that restoring
+-- brings the links back, but they no longer make a project searchable.
+CREATE OR REPLACE FUNCTION public.project_search_vector(p_id INTEGER, p_title TEXT, p_description TEXT)
+RETURNS tsvector
+LANGUAGE sql STABLE AS $$
+  SELECT setweight(to_tsvector('public.portfolio', coalesce(p_title, '')), 'A')
+      || setweight(to_tsvector('public.portfolio', coalesce(p_description, '')), 'B')
+      || setweight(to_tsvector('public.portfolio', coalesce((
+           SELECT string_agg(t.name, ' ')
+           FROM project_tech pt
+           JOIN techs t ON t.id = pt.tech_id
+           WHERE pt.project_id = p_id AND t.deleted_at IS NULL
+         ), '')), 'C')
+$$;
+
+DROP TRIGGER IF EXISTS techs_search_vector ON public.techs;
+CREATE TRIGGER techs_search_vector
+  AFTER UPDATE OF name, deleted_at ON public.techs
+  FOR EACH ROW EXECUTE FUNCTION techs_search_vector_trigger();
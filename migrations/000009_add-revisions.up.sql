@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS public.revisions
+(
+  id         bigserial NOT NULL,
+  entity     TEXT NOT NULL,
+  entity_id  INTEGER NOT NULL,
+  action     TEXT NOT NULL,
+  user_id    INTEGER NULL,
+  api_key_id INTEGER NULL,
+  before     JSONB NULL,
+  after      JSONB NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (id),
+  CONSTRAINT revisions_entity_check CHECK (entity IN ('project', 'technology')),
+  CONSTRAINT revisions_action_check CHECK (action IN ('create', 'update', 'delete', 'restore', 'revert')),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+  FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS revisions_entity_idx
+  ON public.revisions (entity, entity_id, id);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS projects_version_key ON public.projects;
+ALTER TABLE public.projects DROP COLUMN IF EXISTS version_key;
+DROP TABLE IF EXISTS public.releases;
+DROP FUNCTION IF EXISTS public.version_key_trigger();
+DROP FUNCTION IF EXISTS public.semver_key(TEXT);
+DROP FUNCTION IF EXISTS public.semver_number(TEXT);
@@ -0,0 +1,90 @@
+-- semver_number encodes a numeric identifier so that byte order matches
+-- numeric order: its length comes first.
+CREATE OR REPLACE FUNCTION public.semver_number(n TEXT)
+RETURNS TEXT
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT lpad(length(n)::text, 2, '0') || n
+$$;
+
+-- semver_key maps a semantic version to a key whose "C" collation order is
+-- semver precedence: numbers compare numerically, a pre-release sorts
+-- before its release and build metadata is ignored. Strings that aren't
+-- versions get an empty key and sort first.
+CREATE OR REPLACE FUNCTION public.semver_key(v TEXT)
+RETURNS TEXT
+LANGUAGE plpgsql IMMUTABLE AS $$
+DECLARE
+  parts TEXT[];
+  ident TEXT;
+  key   TEXT;
+BEGIN
+  parts := regexp_match(v, '^v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$');
+  IF parts IS NULL THEN
+    RETURN '';
+  END IF;
+  key := semver_number(parts[1]) || '.' || semver_number(parts[2]) || '.' || semver_number(parts[3]);
+  IF parts[4] IS NULL THEN
+    -- "~" sorts after "-", so a release follows its pre-releases.
+    RETURN key || '~';
+  END IF;
+  key := key || '-';
+  -- Numeric identifiers sort before alphanumeric ones; "!" sorts before
+  -- every identifier character, so a shorter list of identifiers comes
+  -- first.
+  FOREACH ident IN ARRAY string_to_array(parts[4], '.') LOOP
+    IF ident ~ '^[0-9]+$' THEN
+      key := key || '0' || semver_number(ident) || '!';
+    ELSE
+      key := key || '1' || ident || '!';
+    END IF;
+  END LOOP;
+  RETURN key;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION public.version_key_trigger()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  NEW.version_key := semver_key(NEW.version);
+  RETURN NEW;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS public.releases
+(
+  id          serial NOT NULL,
+  project_id  INTEGER NOT NULL,
+  version     TEXT NOT NULL,
+  version_key TEXT COLLATE "C" NOT NULL DEFAULT '',
+  released_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  notes       TEXT NOT NULL DEFAULT '',
+  artifacts   TEXT[] NOT NULL DEFAULT '{}',
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (id),
+  CONSTRAINT releases_project_id_version_key UNIQUE (project_id, version),
+  FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS releases_project_id_version_key_idx
+  ON public.releases (project_id, version_key);
+
+CREATE TRIGGER releases_version_key
+  BEFORE INSERT OR UPDATE OF version ON public.releases
+  FOR EACH ROW EXECUTE FUNCTION version_key_trigger();
+
+ALTER TABLE public.projects
+  ADD COLUMN IF NOT EXISTS version_key TEXT COLLATE "C" NOT NULL DEFAULT '';
+
+CREATE TRIGGER projects_version_key
+  BEFORE INSERT OR UPDATE OF version ON public.projects
+  FOR EACH ROW EXECUTE FUNCTION version_key_trigger();
+
+UPDATE public.projects
+SET version_key = semver_key(version);
+
+-- Every project starts with the release of its current version.
+INSERT INTO public.releases (project_id, version, released_at)
+SELECT id, version, created_at
+FROM public.projects;
@@ -0,0 +1,3 @@
+ALTER TABLE public.projects
+  DROP CONSTRAINT IF EXISTS projects_slug_key,
+  DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,35 @@
+ALTER TABLE public.projects
+  ADD COLUMN IF NOT EXISTS slug TEXT NULL;
+
+-- New projects get their slug from the title in the application, which also
+-- transliterates Cyrillic. Existing projects keep only the ASCII letters and
+-- digits of their title, at most 100 characters; titles without any become
+-- "project". Like the application, a slug that is taken gets the first free
+-- suffix -2, -3, ... checked against every slug given so far, so a suffixed
+-- slug can't collide with the slug of another title.
+DO $$
+DECLARE
+  project RECORD;
+  base TEXT;
+  candidate TEXT;
+  n INTEGER;
+BEGIN
+  FOR project IN SELECT id, title FROM public.projects WHERE slug IS NULL ORDER BY id LOOP
+    base := trim(BOTH '-' FROM left(trim(BOTH '-' FROM regexp_replace(lower(project.title), '[^a-z0-9]+', '-', 'g')), 100));
+    IF base = '' THEN
+      base := 'project';
+    END IF;
+    candidate := base;
+    n := 2;
+    WHILE EXISTS (SELECT 1 FROM public.projects WHERE slug = candidate) LOOP
+      candidate := base || '-' || n;
+      n := n + 1;
+    END LOOP;
+    UPDATE public.projects SET slug = candidate WHERE id = project.id;
+  END LOOP;
+END
+$$;
+
+ALTER TABLE public.projects
+  ALTER COLUMN slug SET NOT NULL,
+  ADD CONSTRAINT projects_slug_key UNIQUE (slug);
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations into the binary.
+package migrations
+
+import "embed"
+
+// FS holds the migrations named as golang-migrate expects them:
+// NNNNNN_name.up.sql and NNNNNN_name.down.sql.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,89 @@
+package migrations_test
+
+import (
+	"context"
+	"gowebsite/migrations"
+	"gowebsite/pkg/db/postgres"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// testDB migrates the database of TEST_DATABASE_URL, which should be a
+// scratch database, and skips the test when it isn't set.
+func testDB(t *testing.T) *postgres.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	conn, err := sqlx.ConnectContext(ctx, "postgres", dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	db := &postgres.DB{DB: conn}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := postgres.NewMigrator(ctx, db, migrations.FS)
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	defer migrator.Close()
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return db
+}
+
+func semverKey(t *testing.T, db *postgres.DB, version string) string {
+	t.Helper()
+	var key string
+	if err := db.Get(&key, "SELECT semver_key($1)", version); err != nil {
+		t.Fatalf("semver_key(%q): %v", version, err)
+	}
+	return key
+}
+
+// TestSemverKeyOrder checks that keys compared byte by byte, as the "C"
+// collation of the version_key columns does, follow semver precedence.
+func TestSemverKeyOrder(t *testing.T) {
+	db := testDB(t)
+
+	// Each version precedes the next; the first list is the example of
+	// semver.org §11.
+	orders := [][]string{
+		{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0"},
+		{"1.0.0", "2.0.0", "2.1.0", "2.1.1"},
+		{"1.9.0", "1.10.0", "1.11.0", "10.0.0"},
+		{"1.0.0-9", "1.0.0-10", "1.0.0-a", "1.0.0-a.0", "1.0.0-a-b"},
+		{"1.0.0-rc.1", "1.0.0", "1.0.1-alpha", "1.0.1"},
+	}
+	for _, order := range orders {
+		for i := 1; i < len(order); i++ {
+			prev, next := semverKey(t, db, order[i-1]), semverKey(t, db, order[i])
+			if prev >= next {
+				t.Errorf("semver_key(%q) = %q, not before semver_key(%q) = %q", order[i-1], prev, order[i], next)
+			}
+		}
+	}
+
+	// Build metadata and the v prefix don't change the precedence.
+	equal := [][2]string{
+		{"1.0.0", "1.0.0+build.1"},
+		{"1.0.0-beta", "1.0.0-beta+exp.sha.5114f85"},
+		{"1.2.3", "v1.2.3"},
+	}
+	for _, pair := range equal {
+		if a, b := semverKey(t, db, pair[0]), semverKey(t, db, pair[1]); a != b {
+			t.Errorf("semver_key(%q) = %q, semver_key(%q) = %q, want equal", pair[0], a, pair[1], b)
+		}
+	}
+
+	for _, invalid := range []string{"", "1.0", "01.0.0", "1.0.0-", "latest"} {
+		if key := semverKey(t, db, invalid); key != "" {
+			t.Errorf("semver_key(%q) = %q, want empty", invalid, key)
+		}
+	}
+}
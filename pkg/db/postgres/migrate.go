@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratepg "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+type MigrateConfig struct {
+	// AutoMigrate applies the pending migrations when the server starts.
+	AutoMigrate bool `env:"MIGRATE_ON_START" env-default:"false"`
+	// MigrationsDir is where "migrate create" writes new migrations. The
+	// binary itself runs the migrations embedded when it was built.
+	MigrationsDir string `env:"MIGRATIONS_DIR" env-default:"./migrations"`
+}
+
+// Migrator applies the migrations in files to the database. It keeps its
+// state in the schema_migrations table of golang-migrate, so databases
+// migrated with the migrate CLI carry on where they are.
+type Migrator struct {
+	m     *migrate.Migrate
+	files fs.FS
+}
+
+func NewMigrator(ctx context.Context, db *DB, files fs.FS) (*Migrator, error) {
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	// A connection of its own is closed with the migrator while db stays
+	// open.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := migratepg.WithConnection(ctx, conn, &migratepg.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to prepare migrations: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("failed to prepare migrations: %w", err)
+	}
+	return &Migrator{m: m, files: files}, nil
+}
+
+// Up applies every pending migration. Migrations run under a Postgres
+// advisory lock, so instances starting together apply them only once.
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Steps applies the next n migrations, or rolls back the last -n ones.
+func (m *Migrator) Steps(n int) error {
+	if err := m.m.Steps(n); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// MigrationStatus is where the database is in the list of migrations.
+type MigrationStatus struct {
+	// Version is the last applied migration, 0 when none is.
+	Version uint
+	// Dirty is set when the last migration failed halfway; it has to be
+	// fixed by hand before migrating further.
+	Dirty   bool
+	Latest  uint
+	Pending []string
+}
+
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, err
+	}
+	migrations, err := listMigrations(m.files)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{Version: version, Dirty: dirty}
+	for _, migration := range migrations {
+		status.Latest = max(status.Latest, migration.version)
+		if migration.version > version {
+			status.Pending = append(status.Pending, migration.name)
+		}
+	}
+	return status, nil
+}
+
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.m.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+var migrationName = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+type migrationFile struct {
+	version uint
+	name    string
+}
+
+// listMigrations returns the migrations in files ordered by version.
+func listMigrations(files fs.FS) ([]migrationFile, error) {
+	names, err := fs.Glob(files, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	var migrations []migrationFile
+	for _, name := range names {
+		match := migrationName.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration %s: %w", name, err)
+		}
+		migrations = append(migrations, migrationFile{version: uint(version), name: match[1] + "_" + match[2]})
+	}
+	slices.SortFunc(migrations, func(a, b migrationFile) int { return cmp.Compare(a.version, b.version) })
+	return migrations, nil
+}
+
+var newMigrationName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// CreateMigration writes empty up and down files for the migration
+// following the last one in dir and returns their paths.
+func CreateMigration(dir, name string) ([]string, error) {
+	if !newMigrationName.MatchString(name) {
+		return nil, fmt.Errorf("migration name %q must be lower case letters, digits, - and _", name)
+	}
+	migrations, err := listMigrations(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+	next := uint(1)
+	if len(migrations) > 0 {
+		next = migrations[len(migrations)-1].version + 1
+	}
+
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", next, name, direction))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return paths, err
+		}
+		if err := f.Close(); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
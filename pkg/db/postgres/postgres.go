@@ -22,11 +22,11 @@ type DB struct {
 
 func New(ctx context.Context, config PostgresConfig) (*DB, error) {
 	dsn := fmt.Sprintf("user=%s password=%s dbname=%s sslmode=disable host=%s port=%s", config.UserName, config.Password, config.DbName, config.Host, config.Port)
-	db, err := sqlx.Connect("postgres", dsn)
+	db, err := sqlx.ConnectContext(ctx, "postgres", dsn)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := db.Conn(context.Background()); err != nil {
+	if err := db.PingContext(ctx); err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
 	return &DB{db}, nil
@@ -0,0 +1,269 @@
+// Package feed writes Atom, RSS 2.0 and JSON Feed 1.1 documents.
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Feed is the content of a feed, whatever its format. Entries are written
+// in the order given.
+type Feed struct {
+	// ID identifies the feed permanently, e.g. a tag URI.
+	ID          string
+	Title       string
+	Description string
+	Author      string
+	// Link is the page the feed is about and FeedURL the feed itself.
+	Link    string
+	FeedURL string
+	Updated time.Time
+	Entries []*Entry
+}
+
+type Entry struct {
+	// ID identifies the entry permanently, so readers recognize it when it
+	// is updated.
+	ID         string
+	Title      string
+	Link       string
+	Summary    string
+	Published  time.Time
+	Updated    time.Time
+	Categories []string
+}
+
+// Format is the format a feed is written in.
+type Format string
+
+const (
+	Atom Format = "atom"
+	RSS  Format = "rss"
+	JSON Format = "json"
+)
+
+// ContentType is the media type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case Atom:
+		return "application/atom+xml; charset=utf-8"
+	case RSS:
+		return "application/rss+xml; charset=utf-8"
+	default:
+		return "application/feed+json; charset=utf-8"
+	}
+}
+
+// Write writes feed to w in format.
+func (f *Feed) Write(w io.Writer, format Format) error {
+	switch format {
+	case Atom:
+		return writeXML(w, f.atom())
+	case RSS:
+		return writeXML(w, f.rss())
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(f.json())
+	default:
+		return fmt.Errorf("unknown feed format %q", format)
+	}
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Links   []atomLink   `xml:"link"`
+	Updated string       `xml:"updated"`
+	Author  *atomAuthor  `xml:"author,omitempty"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func (f *Feed) atom() *atomFeed {
+	feed := &atomFeed{
+		ID:    f.ID,
+		Title: f.Title,
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: f.FeedURL},
+			{Rel: "alternate", Type: "text/html", Href: f.Link},
+		},
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+	}
+	if f.Author != "" {
+		feed.Author = &atomAuthor{Name: f.Author}
+	}
+	for _, entry := range f.Entries {
+		atomEntry := &atomEntry{
+			ID:        entry.ID,
+			Title:     entry.Title,
+			Link:      atomLink{Rel: "alternate", Type: "text/html", Href: entry.Link},
+			Published: entry.Published.UTC().Format(time.RFC3339),
+			Updated:   entry.Updated.UTC().Format(time.RFC3339),
+		}
+		if entry.Summary != "" {
+			atomEntry.Summary = &atomText{Type: "text", Text: entry.Summary}
+		}
+		for _, category := range entry.Categories {
+			atomEntry.Categories = append(atomEntry.Categories, atomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, atomEntry)
+	}
+	return feed
+}
+
+type rssFeed struct {
+	XMLName xml.Name    `xml:"rss"`
+	Version string      `xml:"version,attr"`
+	AtomNS  string      `xml:"xmlns:atom,attr"`
+	Channel *rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string      `xml:"title"`
+	Link          string      `xml:"link"`
+	Description   string      `xml:"description"`
+	SelfLink      rssAtomLink `xml:"atom:link"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	Items         []*rssItem  `xml:"item"`
+}
+
+// rssAtomLink is the self link Atom lends RSS feeds.
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Description string   `xml:"description,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	ID          string `xml:",chardata"`
+}
+
+// rss writes entries with their publication date under their ID as guid.
+// RSS has no update date; Atom and JSON Feed readers see updates, and the
+// build date of the channel shows the last one.
+func (f *Feed) rss() *rssFeed {
+	channel := &rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Description,
+		SelfLink:      rssAtomLink{Href: f.FeedURL, Rel: "self", Type: "application/rss+xml"},
+		LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+	}
+	for _, entry := range f.Entries {
+		channel.Items = append(channel.Items, &rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			GUID:        rssGUID{ID: entry.ID},
+			PubDate:     entry.Published.UTC().Format(time.RFC1123Z),
+			Description: entry.Summary,
+			Categories:  entry.Categories,
+		})
+	}
+	return &rssFeed{Version: "2.0", AtomNS: "http://www.w3.org/2005/Atom", Channel: channel}
+}
+
+type jsonFeed struct {
+	Version     string          `json:"version"`
+	Title       string          `json:"title"`
+	HomePageURL string          `json:"home_page_url"`
+	FeedURL     string          `json:"feed_url"`
+	Description string          `json:"description,omitempty"`
+	Authors     []jsonAuthor    `json:"authors,omitempty"`
+	Items       []*jsonFeedItem `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentText   string   `json:"content_text"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func (f *Feed) json() *jsonFeed {
+	feed := &jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Items:       []*jsonFeedItem{},
+	}
+	if f.Author != "" {
+		feed.Authors = []jsonAuthor{{Name: f.Author}}
+	}
+	for _, entry := range f.Entries {
+		feed.Items = append(feed.Items, &jsonFeedItem{
+			ID:            entry.ID,
+			URL:           entry.Link,
+			Title:         entry.Title,
+			ContentText:   entry.Summary,
+			DatePublished: entry.Published.UTC().Format(time.RFC3339),
+			DateModified:  entry.Updated.UTC().Format(time.RFC3339),
+			Tags:          entry.Categories,
+		})
+	}
+	return feed
+}
@@ -0,0 +1,146 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	return &Feed{
+		ID:          "https://example.com/feeds/projects",
+		Title:       "Portfolio: projects",
+		Description: "New projects",
+		Author:      "Portfolio",
+		Link:        "https://example.com/",
+		FeedURL:     "https://example.com/feeds/projects.atom",
+		Updated:     published.Add(time.Hour),
+		Entries: []*Entry{{
+			ID:         "tag:example.com,2025-01-02:projects/7",
+			Title:      "Site <v2> & more",
+			Link:       "https://example.com/projects/site",
+			Summary:    "A <b>site</b>",
+			Published:  published,
+			Updated:    published.Add(time.Hour),
+			Categories: []string{"Go", "SQL"},
+		}},
+	}
+}
+
+func write(t *testing.T, f *Feed, format Format) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := f.Write(&buf, format); err != nil {
+		t.Fatalf("Write(%s) error = %v", format, err)
+	}
+	return buf.String()
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   []string
+	}{
+		{
+			format: Atom,
+			want: []string{
+				`<feed xmlns="http://www.w3.org/2005/Atom">`,
+				`<id>https://example.com/feeds/projects</id>`,
+				`<link rel="self" type="application/atom+xml" href="https://example.com/feeds/projects.atom"></link>`,
+				`<updated>2025-01-02T03:04:05Z</updated>`,
+				`<author>`,
+				`<id>tag:example.com,2025-01-02:projects/7</id>`,
+				`<title>Site &lt;v2&gt; &amp; more</title>`,
+				`<published>2025-01-02T02:04:05Z</published>`,
+				`<summary type="text">A &lt;b&gt;site&lt;/b&gt;</summary>`,
+				`<category term="SQL"></category>`,
+			},
+		},
+		{
+			format: RSS,
+			want: []string{
+				`<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`,
+				`<atom:link href="https://example.com/feeds/projects.atom" rel="self" type="application/rss+xml"></atom:link>`,
+				`<lastBuildDate>Thu, 02 Jan 2025 03:04:05 +0000</lastBuildDate>`,
+				`<guid isPermaLink="false">tag:example.com,2025-01-02:projects/7</guid>`,
+				`<pubDate>Thu, 02 Jan 2025 02:04:05 +0000</pubDate>`,
+				`<category>Go</category>`,
+			},
+		},
+		{
+			format: JSON,
+			want: []string{
+				`"version": "https://jsonfeed.org/version/1.1"`,
+				`"feed_url": "https://example.com/feeds/projects.atom"`,
+				`"id": "tag:example.com,2025-01-02:projects/7"`,
+				`"title": "Site <v2> & more"`,
+				`"date_published": "2025-01-02T02:04:05Z"`,
+				`"date_modified": "2025-01-02T03:04:05Z"`,
+				`"tags": [`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got := write(t, testFeed(), tt.format)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output lacks %s:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+// TestWriteWellFormed parses the output back, so escaping mistakes show up
+// even where no substring is checked.
+func TestWriteWellFormed(t *testing.T) {
+	f := testFeed()
+	f.Entries[0].Summary = "]]> & \"quotes\" \u00e9"
+	for _, format := range []Format{Atom, RSS} {
+		dec := xml.NewDecoder(strings.NewReader(write(t, f, format)))
+		for {
+			_, err := dec.Token()
+			if err != nil {
+				if err != io.EOF {
+					t.Errorf("%s output is not well-formed: %v", format, err)
+				}
+				break
+			}
+		}
+	}
+	var parsed struct {
+		Items []struct {
+			ContentText string `json:"content_text"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(write(t, f, JSON)), &parsed); err != nil {
+		t.Fatalf("JSON output is invalid: %v", err)
+	}
+	if got := parsed.Items[0].ContentText; got != f.Entries[0].Summary {
+		t.Errorf("content_text = %q, want %q", got, f.Entries[0].Summary)
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	f := testFeed()
+	f.Entries = nil
+	f.Author = ""
+	if got := write(t, f, JSON); !strings.Contains(got, `"items": []`) || strings.Contains(got, "authors") {
+		t.Errorf("empty JSON feed =\n%s\nwant an empty items list and no authors", got)
+	}
+	if got := write(t, f, Atom); strings.Contains(got, "<entry>") || strings.Contains(got, "<author>") {
+		t.Errorf("empty Atom feed =\n%s", got)
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := testFeed().Write(&bytes.Buffer{}, Format("html")); err == nil {
+		t.Error("Write(html) succeeded, want error")
+	}
+}
@@ -19,6 +19,7 @@ type Logger interface {
 	Debug(ctx context.Context, msg string, fields ...zap.Field)
 	Warn(ctx context.Context, msg string, fields ...zap.Field)
 	Fatal(ctx context.Context, msg string, fields ...zap.Field)
+	With(fields ...zap.Field) Logger
 }
 
 type logger struct {
@@ -60,9 +61,14 @@ func (l *logger) Fatal(ctx context.Context, msg string, fields ...zap.Field) {
 	l.logger.Fatal(msg, fields...)
 }
 
+// With returns a child logger that adds fields to every entry.
+func (l *logger) With(fields ...zap.Field) Logger {
+	return &logger{logger: l.logger.With(fields...)}
+}
+
 func New() Logger {
 	zapLogger, _ := zap.NewDevelopment()
-	
+
 	defer zapLogger.Sync()
 
 	return &logger{logger: zapLogger}
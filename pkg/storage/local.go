@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores files in a directory. Serving them under the public URL is
+// left to the HTTP server.
+type Local struct {
+	dir       string
+	publicURL string
+}
+
+func NewLocal(dir, publicURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &Local{dir: dir, publicURL: strings.TrimSuffix(publicURL, "/")}, nil
+}
+
+// Dir is the directory files are stored in.
+func (s *Local) Dir() string {
+	return s.dir
+}
+
+func (s *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial file.
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (s *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(name)
+}
+
+func (s *Local) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *Local) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+// path maps key into the storage directory, rejecting keys that would
+// escape it.
+func (s *Local) path(key string) (string, error) {
+	if key == "" || path.Clean("/"+key) != "/"+key {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
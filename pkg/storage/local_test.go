@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testStorage stores, reads and deletes a file through s.
+func testStorage(t *testing.T, s Storage) {
+	t.Helper()
+	ctx := context.Background()
+	key := "projects/1/test.txt"
+	content := "hello"
+
+	if err := s.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	file, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got, err := io.ReadAll(file)
+	file.Close()
+	if err != nil || string(got) != content {
+		t.Errorf("Get() read %q, %v, want %q", got, err, content)
+	}
+	if url := s.URL(key); !strings.HasSuffix(url, "/"+key) {
+		t.Errorf("URL() = %q, want it to end with the key", url)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if file, err := s.Get(ctx, key); err == nil {
+		file.Close()
+		t.Error("Get() of a deleted file succeeded")
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing file error = %v", err)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocal(filepath.Join(dir, "uploads"), "/media/")
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+	testStorage(t, s)
+
+	if got := s.URL("projects/1/a.png"); got != "/media/projects/1/a.png" {
+		t.Errorf("URL() = %q", got)
+	}
+	// The temporary upload files are gone.
+	entries, err := os.ReadDir(filepath.Join(dir, "uploads", "projects", "1"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("storage directory holds %v, %v, want nothing", entries, err)
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	s := &Local{dir: "/srv/uploads"}
+	valid := map[string]string{
+		"a.png":            "/srv/uploads/a.png",
+		"projects/1/a.png": "/srv/uploads/projects/1/a.png",
+		"projects/..a.png": "/srv/uploads/projects/..a.png",
+	}
+	for key, want := range valid {
+		if got, err := s.path(key); err != nil || got != filepath.FromSlash(want) {
+			t.Errorf("path(%q) = %q, %v, want %q", key, got, err, want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"..",
+		"../a.png",
+		"projects/../../a.png",
+		"projects/1/../a.png",
+		"/etc/passwd",
+		"//etc/passwd",
+		"projects//a.png",
+		"projects/./a.png",
+		"projects/",
+	}
+	for _, key := range invalid {
+		if got, err := s.path(key); err == nil {
+			t.Errorf("path(%q) = %q, want error", key, got)
+		}
+	}
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocal(filepath.Join(dir, "uploads"), "/media")
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := s.Put(ctx, "../outside.txt", strings.NewReader("x"), 1, "text/plain"); err == nil {
+		t.Error("Put() outside the storage directory succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "outside.txt")); !os.IsNotExist(err) {
+		t.Errorf("file outside the storage directory exists: %v", err)
+	}
+	if err := s.Delete(ctx, "../uploads"); err == nil {
+		t.Error("Delete() outside the storage directory succeeded")
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3 stores files in a bucket of an S3-compatible object store such as
+// MinIO. The bucket is expected to allow public reads of the stored files.
+type S3 struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+func NewS3(ctx context.Context, config StorageConfig) (*S3, error) {
+	client, err := minio.New(config.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.S3AccessKey, config.S3SecretKey, ""),
+		Secure: config.S3UseSSL,
+		Region: config.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, config.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check s3 bucket: %w", err)
+	}
+	if !exists {
+		err := client.MakeBucket(ctx, config.S3Bucket, minio.MakeBucketOptions{Region: config.S3Region})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create s3 bucket: %w", err)
+		}
+	}
+
+	publicURL := config.PublicURL
+	if publicURL == "" || strings.HasPrefix(publicURL, "/") {
+		publicURL = client.EndpointURL().String() + "/" + config.S3Bucket
+	}
+	return &S3{client: client, bucket: config.S3Bucket, publicURL: strings.TrimSuffix(publicURL, "/")}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// GetObject doesn't fail for missing objects until they are read.
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, err
+	}
+	return object, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *S3) URL(key string) string {
+	return s.publicURL + "/" + key
+}
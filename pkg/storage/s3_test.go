@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+// TestS3 runs against the S3-compatible store of TEST_S3_ENDPOINT, e.g. a
+// local MinIO at localhost:9000, and is skipped when it isn't set. The
+// bucket, TEST_S3_BUCKET or gowebsite-test, is created when missing.
+func TestS3(t *testing.T) {
+	endpoint := os.Getenv("TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("TEST_S3_ENDPOINT is not set")
+	}
+	bucket := os.Getenv("TEST_S3_BUCKET")
+	if bucket == "" {
+		bucket = "gowebsite-test"
+	}
+	s, err := NewS3(context.Background(), StorageConfig{
+		S3Endpoint:  endpoint,
+		S3Region:    "us-east-1",
+		S3Bucket:    bucket,
+		S3AccessKey: os.Getenv("TEST_S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("TEST_S3_SECRET_KEY"),
+		S3UseSSL:    os.Getenv("TEST_S3_USE_SSL") == "true",
+		PublicURL:   "/media",
+	})
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+	testStorage(t, s)
+
+	// A public URL without a host is replaced with the bucket URL.
+	if want := s.client.EndpointURL().String() + "/" + bucket + "/a.png"; s.URL("a.png") != want {
+		t.Errorf("URL() = %q, want %q", s.URL("a.png"), want)
+	}
+}
@@ -0,0 +1,50 @@
+// Package storage keeps uploaded files on the local filesystem or in an
+// S3-compatible object store.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+type StorageConfig struct {
+	// Backend is "local" or "s3".
+	Backend string `env:"STORAGE_BACKEND" env-default:"local"`
+	// PublicURL is the URL files are served under, keys are appended to it.
+	// For s3 a path without a host is replaced with the bucket URL of the
+	// endpoint.
+	PublicURL string `env:"STORAGE_PUBLIC_URL" env-default:"/media"`
+
+	LocalDir string `env:"STORAGE_LOCAL_DIR" env-default:"./uploads"`
+
+	S3Endpoint  string `env:"STORAGE_S3_ENDPOINT" env-default:"localhost:9000"`
+	S3Region    string `env:"STORAGE_S3_REGION" env-default:"us-east-1"`
+	S3Bucket    string `env:"STORAGE_S3_BUCKET" env-default:"gowebsite"`
+	S3AccessKey string `env:"STORAGE_S3_ACCESS_KEY" json:"-"`
+	S3SecretKey string `env:"STORAGE_S3_SECRET_KEY" json:"-"`
+	S3UseSSL    bool   `env:"STORAGE_S3_USE_SSL" env-default:"false"`
+}
+
+// Storage stores files by key. Keys are slash separated paths such as
+// "projects/1/abc.png".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the file stored under key. The caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL of the file stored under key.
+	URL(key string) string
+}
+
+// New returns the storage backend selected by the config.
+func New(ctx context.Context, config StorageConfig) (Storage, error) {
+	switch config.Backend {
+	case "local":
+		return NewLocal(config.LocalDir, config.PublicURL)
+	case "s3":
+		return NewS3(ctx, config)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.Backend)
+	}
+}
@@ -0,0 +1,149 @@
+// Package svg sanitizes untrusted SVG images so they can be embedded in and
+// served from our pages.
+package svg
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MaxSize is the largest SVG document Sanitize accepts, in bytes.
+const MaxSize = 64 << 10
+
+var (
+	ErrTooLarge = fmt.Errorf("svg is larger than %d bytes", MaxSize)
+	ErrNotSVG   = errors.New("document root is not an svg element")
+)
+
+// allowedElements are the SVG elements kept by Sanitize. Anything else,
+// such as script, style, foreignObject, image, a or animate, is removed
+// together with its content.
+var allowedElements = map[string]bool{
+	"svg": true, "g": true, "defs": true, "symbol": true, "use": true, "title": true, "desc": true,
+	"path": true, "rect": true, "circle": true, "ellipse": true, "line": true, "polyline": true, "polygon": true,
+	"text": true, "tspan": true, "textPath": true,
+	"linearGradient": true, "radialGradient": true, "stop": true, "pattern": true, "clipPath": true, "mask": true,
+	"filter": true, "feBlend": true, "feColorMatrix": true, "feComponentTransfer": true, "feComposite": true,
+	"feFlood": true, "feGaussianBlur": true, "feMerge": true, "feMergeNode": true, "feMorphology": true,
+	"feOffset": true, "feFuncA": true, "feFuncR": true, "feFuncG": true, "feFuncB": true,
+}
+
+// Sanitize returns src with everything that can run code or load external
+// resources removed: disallowed elements, event handler attributes, links
+// and url() references that don't point into the document itself. Comments,
+// processing instructions and doctypes are dropped as well.
+func Sanitize(src string) (string, error) {
+	if len(src) > MaxSize {
+		return "", ErrTooLarge
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(src))
+	var out strings.Builder
+	// RawToken keeps namespace prefixes as written but doesn't match end
+	// tags, so open elements are tracked here. skip is the depth of the
+	// removed element being skipped, 0 if none.
+	var open []xml.Name
+	depth, skip, roots := 0, 0, 0
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid svg: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			open = append(open, t.Name)
+			depth++
+			if depth == 1 {
+				roots++
+				if t.Name.Local != "svg" || t.Name.Space != "" || roots > 1 {
+					return "", ErrNotSVG
+				}
+			}
+			if skip > 0 {
+				continue
+			}
+			if t.Name.Space != "" || !allowedElements[t.Name.Local] {
+				skip = depth
+				continue
+			}
+			writeStart(&out, t)
+		case xml.EndElement:
+			if depth == 0 || open[depth-1] != t.Name {
+				return "", fmt.Errorf("invalid svg: unexpected end element </%s>", t.Name.Local)
+			}
+			open = open[:depth-1]
+			depth--
+			if skip > 0 {
+				if depth < skip {
+					skip = 0
+				}
+				continue
+			}
+			out.WriteString("</" + t.Name.Local + ">")
+		case xml.CharData:
+			if skip == 0 && depth > 0 {
+				_ = xml.EscapeText(&out, t)
+			}
+		}
+	}
+	if depth > 0 {
+		return "", errors.New("invalid svg: unexpected end of document")
+	}
+	if roots == 0 {
+		return "", ErrNotSVG
+	}
+	return out.String(), nil
+}
+
+func writeStart(out *strings.Builder, start xml.StartElement) {
+	out.WriteString("<" + start.Name.Local)
+	for _, attr := range start.Attr {
+		name := attr.Name.Local
+		if attr.Name.Space != "" {
+			name = attr.Name.Space + ":" + name
+		}
+		if !allowedAttr(attr) {
+			continue
+		}
+		out.WriteString(" " + name + `="`)
+		_ = xml.EscapeText(out, []byte(attr.Value))
+		out.WriteString(`"`)
+	}
+	out.WriteString(">")
+}
+
+func allowedAttr(attr xml.Attr) bool {
+	local := strings.ToLower(attr.Name.Local)
+	value := strings.ToLower(strings.Join(strings.Fields(attr.Value), ""))
+	switch {
+	case strings.HasPrefix(local, "on"):
+		return false
+	case attr.Name.Space == "xmlns" || (attr.Name.Space == "" && local == "xmlns"):
+		return true
+	case attr.Name.Space != "" && attr.Name.Space != "xlink" && attr.Name.Space != "xml":
+		return false
+	case local == "href":
+		return strings.HasPrefix(value, "#")
+	case strings.Contains(value, "javascript:"), strings.Contains(value, "data:"),
+		strings.Contains(value, "expression("), strings.Contains(value, "@import"):
+		return false
+	}
+	// url() may only reference elements of the document, e.g. gradients.
+	for rest := value; ; {
+		i := strings.Index(rest, "url(")
+		if i < 0 {
+			return true
+		}
+		rest = strings.TrimLeft(rest[i+len("url("):], `'"`)
+		if !strings.HasPrefix(rest, "#") {
+			return false
+		}
+	}
+}
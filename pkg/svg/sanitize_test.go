@@ -0,0 +1,147 @@
+package svg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "keeps drawing elements",
+			src:  `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><path d="M0 0h10v10z" fill="red"/></svg>`,
+			want: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><path d="M0 0h10v10z" fill="red"></path></svg>`,
+		},
+		{
+			name: "removes script with its content",
+			src:  `<svg><script>alert(1)</script><rect width="1"/></svg>`,
+			want: `<svg><rect width="1"></rect></svg>`,
+		},
+		{
+			name: "removes script nested in a removed element",
+			src:  `<svg><a href="#x"><script>alert(1)</script><rect/></a><circle r="1"/></svg>`,
+			want: `<svg><circle r="1"></circle></svg>`,
+		},
+		{
+			name: "removes namespaced script",
+			src:  `<svg xmlns:h="http://www.w3.org/1999/xhtml"><h:script>alert(1)</h:script></svg>`,
+			want: `<svg xmlns:h="http://www.w3.org/1999/xhtml"></svg>`,
+		},
+		{
+			name: "removes event handlers",
+			src:  `<svg onload="alert(1)"><rect onClick="alert(2)" OnMouseOver="alert(3)" width="1"/></svg>`,
+			want: `<svg><rect width="1"></rect></svg>`,
+		},
+		{
+			name: "removes foreignObject with its content",
+			src:  `<svg><foreignObject><body xmlns="http://www.w3.org/1999/xhtml"><iframe src="https://evil.example"/></body></foreignObject></svg>`,
+			want: `<svg></svg>`,
+		},
+		{
+			name: "removes style elements",
+			src:  `<svg><style>@import url(https://evil.example/x.css);</style></svg>`,
+			want: `<svg></svg>`,
+		},
+		{
+			name: "keeps internal href",
+			src:  `<svg xmlns:xlink="http://www.w3.org/1999/xlink"><use href="#a"/><use xlink:href="#b"/></svg>`,
+			want: `<svg xmlns:xlink="http://www.w3.org/1999/xlink"><use href="#a"></use><use xlink:href="#b"></use></svg>`,
+		},
+		{
+			name: "removes external href",
+			src:  `<svg><use href="https://evil.example/sprite.svg#a"/></svg>`,
+			want: `<svg><use></use></svg>`,
+		},
+		{
+			name: "removes external xlink:href",
+			src:  `<svg xmlns:xlink="http://www.w3.org/1999/xlink"><use xlink:href="//evil.example/sprite.svg#a"/></svg>`,
+			want: `<svg xmlns:xlink="http://www.w3.org/1999/xlink"><use></use></svg>`,
+		},
+		{
+			name: "removes javascript href",
+			src:  `<svg xmlns:xlink="http://www.w3.org/1999/xlink"><use xlink:href=" java&#x09;script:alert(1)"/></svg>`,
+			want: `<svg xmlns:xlink="http://www.w3.org/1999/xlink"><use></use></svg>`,
+		},
+		{
+			name: "removes data href",
+			src:  `<svg><use href="data:image/svg+xml;base64,PHN2Zz4="/></svg>`,
+			want: `<svg><use></use></svg>`,
+		},
+		{
+			name: "keeps internal url references",
+			src:  `<svg><rect fill="url(#g)" style="fill: url('#g')"/></svg>`,
+			want: `<svg><rect fill="url(#g)" style="fill: url(&#39;#g&#39;)"></rect></svg>`,
+		},
+		{
+			name: "removes external url in style",
+			src:  `<svg><rect style="fill: url( 'https://evil.example/x.svg#g' )" width="1"/></svg>`,
+			want: `<svg><rect width="1"></rect></svg>`,
+		},
+		{
+			name: "removes external url after an internal one",
+			src:  `<svg><rect style="fill: url(#g); stroke: URL(https://evil.example/)"/></svg>`,
+			want: `<svg><rect></rect></svg>`,
+		},
+		{
+			name: "removes javascript and data values of other attributes",
+			src:  `<svg><rect style="background: javascript:alert(1)" fill="data:text/html,x" width="1"/></svg>`,
+			want: `<svg><rect width="1"></rect></svg>`,
+		},
+		{
+			name: "removes attributes of unknown namespaces",
+			src:  `<svg xmlns:ev="http://www.w3.org/2001/xml-events"><rect ev:event="click" width="1"/></svg>`,
+			want: `<svg xmlns:ev="http://www.w3.org/2001/xml-events"><rect width="1"></rect></svg>`,
+		},
+		{
+			name: "drops comments, processing instructions and doctype",
+			src:  `<?xml version="1.0"?><!DOCTYPE svg><!-- c --><svg><?pi x?><title>T &amp; <!-- c -->U</title></svg>`,
+			want: `<svg><title>T &amp; U</title></svg>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize(tt.src)
+			if err != nil {
+				t.Fatalf("Sanitize() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Sanitize() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr error
+	}{
+		{name: "empty", src: ``, wantErr: ErrNotSVG},
+		{name: "not svg", src: `<html><script>alert(1)</script></html>`, wantErr: ErrNotSVG},
+		{name: "namespaced root", src: `<x:svg xmlns:x="http://www.w3.org/2000/svg"></x:svg>`, wantErr: ErrNotSVG},
+		{name: "two roots", src: `<svg></svg><svg></svg>`, wantErr: ErrNotSVG},
+		{name: "too large", src: `<svg>` + strings.Repeat(" ", MaxSize) + `</svg>`, wantErr: ErrTooLarge},
+		{name: "unclosed element", src: `<svg><rect>`},
+		{name: "mismatched end element", src: `<svg><g></rect></svg>`},
+		{name: "stray end element", src: `<svg></svg></g>`},
+		{name: "unquoted attribute", src: `<svg width=1></svg>`},
+		{name: "bad entity", src: `<svg><title>&bogus;</title></svg>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize(tt.src)
+			if err == nil {
+				t.Fatalf("Sanitize() = %q, want error", got)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Sanitize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}